@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// runAction is the entrypoint of the GitHub Action of action.yml: the files come from the inputs, relative to the
+// workspace, the pushed or pull request commits tell whether any of them changed, and the findings become
+// annotations, a job summary and outputs
+func runAction(ctx context.Context, args []string) error {
+	var a githubAction
+	fs := flag.NewFlagSet("action", flag.ExitOnError)
+	fs.StringVar(&a.maintainersFilePath, "maintainers-file", actionInput("maintainers-file", defaultMaintainersFile), "path to the maintainers file, the maintainers-file input by default")
+	fs.StringVar(&a.indexFilePath, "index-file", actionInput("index-file", defaultIndexFile), "path, http(s), oci:// or helm-cache:// URL of the chart repository index file, the index-file input by default")
+	fs.StringVar(&a.configFilePath, "config", actionInput("config", defaultConfigFile), "path to the config file holding the chart aliases, the config input by default")
+	fs.BoolVar(&a.failOnWarnings, "fail-on-warnings", actionInput("fail-on-warnings", "false") == "true", "fail the step on warnings too, the fail-on-warnings input by default")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		if err := os.Chdir(workspace); err != nil {
+			return fmt.Errorf("error: failed to enter the workspace [%s]: %w", workspace, err)
+		}
+	}
+	if changed, ok := eventChangedFiles(os.Getenv("GITHUB_EVENT_NAME"), os.Getenv("GITHUB_EVENT_PATH")); ok && !a.relevant(changed) {
+		fmt.Printf("neither [%s], [%s] nor [%s] changed, skipping validation\n", a.maintainersFilePath, a.indexFilePath, a.configFilePath)
+		return a.writeOutputs(nil, true)
+	}
+
+	config, err := loadConfig(a.configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, a.maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	index, err := decodeIndexFile(ctx, a.indexFilePath)
+	if err != nil {
+		return err
+	}
+	results, err := validateMaintainersResults(ctx, config, maintainers, index, a.maintainersFilePath, a.indexFilePath)
+	if err != nil {
+		return err
+	}
+	a.annotate(results)
+	if err := a.writeSummary(results); err != nil {
+		return err
+	}
+	if err := a.writeOutputs(results, false); err != nil {
+		return err
+	}
+	warnings := len(results) - countErrors(results)
+	if results.HasErrors() || (a.failOnWarnings && warnings > 0) {
+		return exitCode(1)
+	}
+	return nil
+}
+
+type githubAction struct {
+	maintainersFilePath string
+	indexFilePath       string
+	configFilePath      string
+	failOnWarnings      bool
+}
+
+// actionInput returns the input of the action, which GitHub passes as INPUT_<NAME>, keeping or replacing the dashes
+// of the name depending on the kind of action
+func actionInput(name, fallback string) string {
+	upper := "INPUT_" + strings.ToUpper(name)
+	for _, env := range []string{upper, strings.ReplaceAll(upper, "-", "_")} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	return fallback
+}
+
+// eventChangedFiles returns the files changed by the pull request or push of the event, ok is false when they
+// cannot be told, e.g. for other events, a new branch or a shallow checkout missing the base commit, and everything
+// is then validated
+func eventChangedFiles(eventName, eventPath string) ([]string, bool) {
+	if eventPath == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, false
+	}
+	var event struct {
+		Before      string `json:"before"`
+		After       string `json:"after"`
+		PullRequest struct {
+			Base struct {
+				SHA string `json:"sha"`
+			} `json:"base"`
+			Head struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, false
+	}
+	var from, to string
+	switch eventName {
+	case "pull_request", "pull_request_target":
+		from, to = event.PullRequest.Base.SHA, event.PullRequest.Head.SHA
+	case "push":
+		from, to = event.Before, event.After
+	default:
+		return nil, false
+	}
+	if from == "" || to == "" || strings.Trim(from, "0") == "" {
+		return nil, false
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "diff", "--name-only", from, to)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		slog.Debug("failed to list the changed files, validating everything", "from", from, "to", to, "error", strings.TrimSpace(stderr.String()))
+		return nil, false
+	}
+	return splitLines(string(out)), true
+}
+
+// relevant reports whether one of the changed files is the maintainers file, one of its team files, the index or
+// the config file. A remote index can change at any time and is always relevant
+func (a githubAction) relevant(changed []string) bool {
+	if strings.Contains(a.indexFilePath, "://") {
+		return true
+	}
+	for _, file := range changed {
+		for _, path := range []string{a.maintainersFilePath, a.indexFilePath, a.configFilePath} {
+			path = filepath.ToSlash(filepath.Clean(path))
+			if file == path || strings.HasPrefix(file, path+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// annotate prints a workflow command per result, at the line of its chart in the maintainers file, or in the team
+// file of a maintainers directory, when there is one
+func (a githubAction) annotate(results validate.Results) {
+	locations := chartLocations(a.maintainersFilePath)
+	for _, r := range results {
+		command := "error"
+		if r.Severity == validate.SeverityWarning {
+			command = "warning"
+		}
+		file, line := a.maintainersFilePath, 0
+		if location, ok := locations[r.Chart]; ok {
+			file, line = location.file, location.line
+		}
+		props := "file=" + escapeActionProperty(filepath.ToSlash(filepath.Clean(file)))
+		if line > 0 {
+			props += fmt.Sprintf(",line=%d", line)
+		}
+		props += ",title=" + escapeActionProperty("cowhand "+r.Rule)
+		fmt.Printf("::%s %s::%s\n", command, props, escapeActionData(r.Message))
+	}
+}
+
+// chartLocation is the file and the line of the name of a chart
+type chartLocation struct {
+	file string
+	line int
+}
+
+// chartLocations maps the charts of the maintainers file, or of every team file of a maintainers directory, to where
+// their name is. A chart listed twice is at its first listing, and a file that does not decode has no locations
+func chartLocations(path string) map[string]chartLocation {
+	locations := make(map[string]chartLocation)
+	files := []string{path}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		if files, err = maintainers.TeamFiles(path); err != nil {
+			return locations
+		}
+	}
+	for _, file := range files {
+		for name, line := range chartLines(file) {
+			if _, ok := locations[name]; !ok {
+				locations[name] = chartLocation{file, line}
+			}
+		}
+	}
+	return locations
+}
+
+// chartLines maps the charts of the maintainers file to the line of their name, empty for a file that does not
+// decode
+func chartLines(path string) map[string]int {
+	lines := make(map[string]int)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return lines
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return lines
+	}
+	for _, team := range teamNodes(&doc) {
+		charts := mappingValue(team, "charts")
+		if charts == nil {
+			continue
+		}
+		for _, chart := range charts.Content {
+			if name := mappingValue(chart, "name"); name != nil {
+				if _, ok := lines[name.Value]; !ok {
+					lines[name.Value] = name.Line
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// escapeActionData and escapeActionProperty escape the message and the properties of a workflow command
+func escapeActionData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeActionProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// writeSummary appends the findings as a table to the job summary
+func (a githubAction) writeSummary(results validate.Results) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	summary := actionSummary(a.maintainersFilePath, a.indexFilePath, results)
+	if _, err := io.WriteString(file, summary); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func actionSummary(maintainersFilePath, indexFilePath string, results validate.Results) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## cowhand\n\n")
+	if len(results) == 0 {
+		fmt.Fprintf(&b, "`%s` is valid against `%s`.\n", maintainersFilePath, indexFilePath)
+		return b.String()
+	}
+	errs := countErrors(results)
+	fmt.Fprintf(&b, "`%s` against `%s`: %s and %s.\n\n", maintainersFilePath, indexFilePath, pluralize(errs, "error"), pluralize(len(results)-errs, "warning"))
+	b.WriteString("| Severity | Rule | Chart | Message |\n| --- | --- | --- | --- |\n")
+	for _, r := range results {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", r.Severity, r.Rule, r.Chart, strings.ReplaceAll(r.Message, "|", "\\|"))
+	}
+	return b.String()
+}
+
+// writeOutputs sets the errors, warnings, valid and skipped outputs of the step
+func (a githubAction) writeOutputs(results validate.Results, skipped bool) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	errs := countErrors(results)
+	_, err = fmt.Fprintf(file, "errors=%d\nwarnings=%d\nvalid=%t\nskipped=%t\n", errs, len(results)-errs, errs == 0, skipped)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func countErrors(results validate.Results) int {
+	n := 0
+	for _, r := range results {
+		if r.Severity == validate.SeverityError {
+			n++
+		}
+	}
+	return n
+}
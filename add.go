@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runAdd(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		interactive         bool
+	)
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file the charts are completed from")
+	fs.BoolVar(&interactive, "interactive", false, "prompt for the team, its contacts, charts and labels")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if !interactive {
+		return errors.New("error: usage: cowhand add --interactive, use add-chart to add a chart without prompts")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	// The wizard still works without an index, charts are just not completed nor checked
+	index, err := decodeIndexFile(ctx, indexFilePath)
+	if err != nil {
+		fmt.Printf("warning: charts will not be completed: %v\n", err)
+		index = repo.NewIndexFile()
+	}
+	w := &wizard{in: bufio.NewScanner(os.Stdin), out: os.Stdout}
+	added, err := w.run(doc, maintainers, index)
+	if err != nil {
+		return err
+	}
+	if added == 0 {
+		fmt.Println("Nothing to add")
+		return nil
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if ok, err := w.confirm(fmt.Sprintf("Write these changes to [%s]?", maintainersFilePath), true); err != nil || !ok {
+		return err
+	}
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote [%s]\n", maintainersFilePath)
+	return nil
+}
+
+// wizard asks the questions of add --interactive, asking again until each answer is valid
+type wizard struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// run prompts for a team, creating it with its contacts if it is new, then for its charts until an empty answer, and
+// adds them to doc. It returns how many charts were added
+func (w *wizard) run(doc *yaml.Node, maintainers Maintainers, index *repo.IndexFile) (int, error) {
+	team, isNew, err := w.askTeam(maintainers)
+	if err != nil {
+		return 0, err
+	}
+	if isNew {
+		fields := make(map[string]string)
+		for _, field := range []struct{ key, question string }{
+			{"email", "Email of the team"},
+			{"slackChannel", "Slack channel of the team"},
+			{"url", "URL of the team, e.g. its wiki page"},
+		} {
+			value, err := w.askValid(field.question, "", func(value string) error {
+				return validateContactFields(map[string]string{field.key: value})
+			})
+			if err != nil {
+				return 0, err
+			}
+			if value != "" {
+				fields[field.key] = value
+			}
+		}
+		node := newTeamNode(team)
+		setContactNode(node, fields)
+		doc.Content[0].Content = append(doc.Content[0].Content, node)
+		maintainers = append(maintainers, &Maintainer{Name: team})
+	}
+
+	added := make(map[string]struct{})
+	for {
+		chartName, err := w.askChart(maintainers, index, added)
+		if err != nil || chartName == "" {
+			return len(added), err
+		}
+		chart := Chart{Name: chartName, GithubLabels: []string{}}
+		labels, err := w.ask("GitHub labels, comma separated", teamLabel(team))
+		if err != nil {
+			return len(added), err
+		}
+		for _, label := range strings.Split(labels, ",") {
+			if label = strings.TrimSpace(label); label != "" && !containsString(chart.GithubLabels, label) {
+				chart.GithubLabels = append(chart.GithubLabels, label)
+			}
+		}
+		// crd charts are tracked in the issue of the chart they belong to
+		if !strings.HasSuffix(chartName, "-crd") {
+			if chart.GenerateIssue, err = w.confirm("Create a tracking issue on every release?", false); err != nil {
+				return len(added), err
+			}
+		}
+		var value yaml.Node
+		if err := value.Encode(chart); err != nil {
+			return len(added), err
+		}
+		if err := insertChartNode(doc, team, chartName, &value); err != nil {
+			return len(added), err
+		}
+		added[chartName] = struct{}{}
+	}
+}
+
+// askTeam returns the name of the team the charts are added to and whether it has to be created
+func (w *wizard) askTeam(maintainers Maintainers) (string, bool, error) {
+	for {
+		answer, err := w.ask("Team, the name of an existing team or part of it, or of a new team", "")
+		if err != nil {
+			return "", false, err
+		}
+		if answer == "" {
+			continue
+		}
+		m, err := maintainers.FindTeam(answer)
+		if err == nil {
+			fmt.Fprintf(w.out, "using team [%s]\n", m.Name)
+			return m.Name, false, nil
+		}
+		if strings.Contains(err.Error(), "ambiguous") {
+			fmt.Fprintln(w.out, "error:", err)
+			continue
+		}
+		ok, err := w.confirm(fmt.Sprintf("Create team [%s]?", answer), false)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return answer, true, nil
+		}
+	}
+}
+
+// askChart returns the next chart to add or "" when done. An answer that is not a chart of the index lists the
+// charts it prefixes, a single one of them is completed
+func (w *wizard) askChart(maintainers Maintainers, index *repo.IndexFile, added map[string]struct{}) (string, error) {
+	for {
+		answer, err := w.ask("Chart to add, empty to finish", "")
+		if err != nil || answer == "" {
+			return "", err
+		}
+		if _, ok := index.Entries[answer]; !ok {
+			var candidates []string
+			for _, name := range validate.IndexChartNames(index) {
+				if strings.HasPrefix(name, answer) {
+					candidates = append(candidates, name)
+				}
+			}
+			switch {
+			case len(candidates) == 1:
+				answer = candidates[0]
+				fmt.Fprintf(w.out, "completed to [%s]\n", answer)
+			case len(candidates) > 1:
+				fmt.Fprintf(w.out, "matching charts: %s\n", strings.Join(candidates, ", "))
+				continue
+			case len(index.Entries) > 0:
+				ok, err := w.confirm(fmt.Sprintf("Chart [%s] is not in the index, add it anyway?", answer), false)
+				if err != nil {
+					return "", err
+				}
+				if !ok {
+					continue
+				}
+			}
+		}
+		if m, _ := maintainers.FindChart(answer); m != nil {
+			fmt.Fprintf(w.out, "error: chart [%s] is already maintained by [%s]\n", answer, m.Name)
+			continue
+		}
+		if _, ok := added[answer]; ok {
+			fmt.Fprintf(w.out, "error: chart [%s] was already added\n", answer)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// ask prints the question with its default and returns the trimmed answer, or the default if it is empty
+func (w *wizard) ask(question, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	if !w.in.Scan() {
+		if err := w.in.Err(); err != nil {
+			return "", err
+		}
+		return "", errors.New("error: input ended before the wizard finished, nothing was written")
+	}
+	if answer := strings.TrimSpace(w.in.Text()); answer != "" {
+		return answer, nil
+	}
+	return defaultValue, nil
+}
+
+// askValid asks until validate accepts the answer
+func (w *wizard) askValid(question, defaultValue string, validate func(string) error) (string, error) {
+	for {
+		answer, err := w.ask(question, defaultValue)
+		if err != nil {
+			return "", err
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintln(w.out, err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// confirm asks a yes or no question
+func (w *wizard) confirm(question string, defaultValue bool) (bool, error) {
+	choices := "y/N"
+	if defaultValue {
+		choices = "Y/n"
+	}
+	for {
+		answer, err := w.ask(question+" ["+choices+"]", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func runAddChart(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		team                string
+		labels              string
+		generateIssue       bool
+	)
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("add-chart", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&team, "team", "", "name of the team maintaining the chart, or a unique part of it such as team/area1")
+	fs.StringVar(&labels, "labels", "", "comma separated GitHub labels of the chart")
+	fs.BoolVar(&generateIssue, "generate-issue", false, "create a tracking issue for the chart on every release")
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" || team == "" {
+		return errors.New("error: usage: cowhand add-chart <chart> --team <name> [--labels a,b] [--generate-issue]")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	if m, _ := maintainers.FindChart(chartName); m != nil {
+		return fmt.Errorf("error: chart [%s] is already maintained by [%s]", chartName, m.Name)
+	}
+	m, err := maintainers.FindTeam(team)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	chart := Chart{Name: chartName, GenerateIssue: generateIssue, GithubLabels: []string{}}
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			chart.GithubLabels = append(chart.GithubLabels, label)
+		}
+	}
+
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	var value yaml.Node
+	if err := value.Encode(chart); err != nil {
+		return err
+	}
+	if err := insertChartNode(doc, m.Name, chart.Name, &value); err != nil {
+		return err
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\nadded chart [%s] to [%s]\n", chartName, m.Name)
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// apiServer answers ownership queries over HTTP from a maintainers file it reloads every interval, so that other
+// tools do not have to parse it. With an index, every reload also validates the file for the ownership metrics
+type apiServer struct {
+	maintainersFilePath string
+	indexFilePath       string
+	config              *Config
+	interval            time.Duration
+	metrics             *serverMetrics
+
+	mu          sync.RWMutex
+	maintainers Maintainers
+	loadedAt    time.Time
+	// validation is that of the last reload that loaded the index, nil without an index
+	validation *apiValidation
+}
+
+// apiValidation is a validation of the maintainers file against the index, shown by the dashboard
+type apiValidation struct {
+	findings    validate.Results
+	unowned     []string
+	indexCharts int
+	validatedAt time.Time
+}
+
+// chartOwnership is the response of GET /v1/charts/{name}/maintainer
+type chartOwnership struct {
+	Chart              string   `json:"chart"`
+	Team               string   `json:"team"`
+	Contact            Contact  `json:"contact"`
+	MaintainedVersions string   `json:"maintainedVersions,omitempty"`
+	Repositories       []string `json:"repositories,omitempty"`
+	GithubLabels       []string `json:"githubLabels,omitempty"`
+}
+
+func (s *apiServer) register(mux *http.ServeMux, metrics *serverMetrics) {
+	mux.Handle("GET /v1/teams", metrics.instrument("teams", http.HandlerFunc(s.teams)))
+	mux.Handle("GET /v1/teams/{name}", metrics.instrument("team", http.HandlerFunc(s.team)))
+	mux.Handle("GET /v1/charts/{name}/maintainer", metrics.instrument("chart-maintainer", http.HandlerFunc(s.chartMaintainer)))
+	mux.Handle("GET /{$}", metrics.instrument("dashboard", http.HandlerFunc(s.dashboard)))
+}
+
+// load decodes the maintainers file, keeping the teams loaded before if it fails, then validates it against the
+// index if there is one
+func (s *apiServer) load(ctx context.Context) error {
+	maintainers, err := decodeMaintainersFile(ctx, s.maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.maintainers, s.loadedAt = maintainers, time.Now()
+	s.mu.Unlock()
+	if s.indexFilePath == "" {
+		return nil
+	}
+	index, err := decodeIndexFile(ctx, s.indexFilePath)
+	if err != nil {
+		return err
+	}
+	results, err := validateMaintainersResults(ctx, s.config, maintainers, index, s.maintainersFilePath, s.indexFilePath)
+	if err != nil {
+		return err
+	}
+	s.metrics.recordValidation(s.maintainersFilePath, results)
+	validation := &apiValidation{
+		findings:    results,
+		unowned:     validate.OrphanCharts(s.config.Aliases, maintainers, index),
+		indexCharts: len(validate.IndexChartNames(index)),
+		validatedAt: time.Now(),
+	}
+	s.mu.Lock()
+	s.validation = validation
+	s.mu.Unlock()
+	return nil
+}
+
+// reload loads the maintainers file every interval until ctx is done
+func (s *apiServer) reload(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.load(ctx); err != nil {
+				slog.Error("failed to reload the maintainers file", "path", s.maintainersFilePath, "index", s.indexFilePath, "error", err)
+			}
+		}
+	}
+}
+
+func (s *apiServer) snapshot() (Maintainers, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.maintainers, s.loadedAt
+}
+
+func (s *apiServer) lastValidation() *apiValidation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.validation
+}
+
+func (s *apiServer) teams(w http.ResponseWriter, r *http.Request) {
+	maintainers, loadedAt := s.snapshot()
+	teams := make([]teamSummary, 0, len(maintainers))
+	for _, m := range maintainers {
+		teams = append(teams, teamSummary{
+			Name:         m.Name,
+			Email:        m.Contact.Email,
+			SlackChannel: m.Contact.SlackChannel,
+			URL:          m.Contact.URL,
+			Charts:       len(m.Charts),
+		})
+	}
+	writeJSON(w, http.StatusOK, loadedAt, teams)
+}
+
+func (s *apiServer) team(w http.ResponseWriter, r *http.Request) {
+	maintainers, loadedAt := s.snapshot()
+	m, _ := maintainers.FindTeamByName(r.PathValue("name"))
+	if m == nil {
+		writeJSONError(w, http.StatusNotFound, fmt.Sprintf("team [%s] is not in the maintainers file", r.PathValue("name")))
+		return
+	}
+	writeJSON(w, http.StatusOK, loadedAt, m)
+}
+
+func (s *apiServer) chartMaintainer(w http.ResponseWriter, r *http.Request) {
+	maintainers, loadedAt := s.snapshot()
+	m, chart, err := s.findChart(maintainers, r.PathValue("name"), r.URL.Query().Get("version"))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, loadedAt, chartOwnership{
+		Chart:              chart.Name,
+		Team:               m.Name,
+		Contact:            m.Contact,
+		MaintainedVersions: chart.MaintainedVersions,
+		Repositories:       chart.Repositories,
+		GithubLabels:       chart.GithubLabels,
+	})
+}
+
+// findChart returns the chart and the team maintaining it, also found by one of its aliases, and with a version the
+// team maintaining that version
+func (s *apiServer) findChart(maintainers Maintainers, name, version string) (*Maintainer, *Chart, error) {
+	canonical := validate.CanonicalChartName(s.config.Aliases, name)
+	for _, m := range maintainers {
+		for i := range m.Charts {
+			chart := &m.Charts[i]
+			if chart.Name != name && validate.CanonicalChartName(s.config.Aliases, chart.Name) != canonical {
+				continue
+			}
+			if version != "" && !chart.Maintains(version) {
+				continue
+			}
+			return m, chart, nil
+		}
+	}
+	if version != "" {
+		return nil, nil, fmt.Errorf("version [%s] of chart [%s] is not maintained by any team", version, name)
+	}
+	return nil, nil, fmt.Errorf("chart [%s] is not in the maintainers file", name)
+}
+
+// writeJSON writes v as the response, Last-Modified is when the maintainers file was loaded
+func writeJSON(w http.ResponseWriter, status int, loadedAt time.Time, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if !loadedAt.IsZero() {
+		w.Header().Set("Last-Modified", loadedAt.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeJSONError writes the message as an error response, without the "error: " the errors of the commands start with
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, time.Time{}, struct {
+		Error string `json:"error"`
+	}{strings.TrimPrefix(message, "error: ")})
+}
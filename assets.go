@@ -0,0 +1,393 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	sigyaml "sigs.k8s.io/yaml"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// chartAsset is the metadata of a packaged chart archive or of a chart directory under packages/
+type chartAsset struct {
+	path     string
+	metadata *chart.Metadata
+	// dependencies are the names of the charts listed in Chart.yaml or Chart.lock
+	dependencies []string
+}
+
+// loadChartAssets reads the Chart.yaml and Chart.lock of every .tgz archive under dir of fsys, sorted by path
+func loadChartAssets(fsys fs.FS, dir string) ([]*chartAsset, error) {
+	var assets []*chartAsset
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".tgz") {
+			return nil
+		}
+		asset, err := readArchive(fsys, p)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, asset)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].path < assets[j].path })
+	return assets, nil
+}
+
+// readArchive decodes the top level Chart.yaml and Chart.lock of a chart archive without extracting the rest of it
+func readArchive(fsys fs.FS, p string) (*chartAsset, error) {
+	file, err := fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read chart archive [%s]: %w", p, err)
+	}
+	defer gz.Close()
+	var chartYAML, chartLock []byte
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error: failed to read chart archive [%s]: %w", p, err)
+		}
+		// Archives hold a single <chart>/ directory, files further down belong to subcharts
+		if header.Typeflag != tar.TypeReg || strings.Count(path.Clean(header.Name), "/") != 1 {
+			continue
+		}
+		switch path.Base(header.Name) {
+		case "Chart.yaml":
+			chartYAML, err = io.ReadAll(tr)
+		case "Chart.lock":
+			chartLock, err = io.ReadAll(tr)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if chartYAML == nil {
+		return nil, fmt.Errorf("error: chart archive [%s] has no Chart.yaml", p)
+	}
+	return decodeChartAsset(p, chartYAML, chartLock)
+}
+
+// loadPackageCharts reads the Chart.yaml and Chart.lock of every chart directory under dir of fsys, skipping
+// subcharts
+func loadPackageCharts(fsys fs.FS, dir string) ([]*chartAsset, error) {
+	var charts []*chartAsset
+	err := walkCharts(fsys, dir, func(chartYAMLPath string) error {
+		chartYAML, err := fs.ReadFile(fsys, chartYAMLPath)
+		if err != nil {
+			return err
+		}
+		chartLock, err := fs.ReadFile(fsys, path.Join(path.Dir(chartYAMLPath), "Chart.lock"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		asset, err := decodeChartAsset(chartYAMLPath, chartYAML, chartLock)
+		if err != nil {
+			return err
+		}
+		charts = append(charts, asset)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return charts, nil
+}
+
+// walkCharts calls fn with the path of every Chart.yaml under dir of fsys in lexical order, the charts/ directory
+// of a chart holds its subcharts and is not walked
+func walkCharts(fsys fs.FS, dir string, fn func(chartYAMLPath string) error) error {
+	return fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "charts" && p != dir {
+				if _, err := fs.Stat(fsys, path.Join(path.Dir(p), "Chart.yaml")); err == nil {
+					return fs.SkipDir
+				}
+			}
+			return nil
+		}
+		if d.Name() != "Chart.yaml" {
+			return nil
+		}
+		return fn(p)
+	})
+}
+
+func decodeChartAsset(p string, chartYAML, chartLock []byte) (*chartAsset, error) {
+	metadata := &chart.Metadata{}
+	if err := sigyaml.Unmarshal(chartYAML, metadata); err != nil {
+		return nil, fmt.Errorf("error: failed to decode the Chart.yaml of [%s]: %w", p, err)
+	}
+	asset := &chartAsset{path: p, metadata: metadata}
+	seen := make(map[string]struct{})
+	addDependencies := func(dependencies []*chart.Dependency) {
+		for _, d := range dependencies {
+			if d == nil {
+				continue
+			}
+			if _, ok := seen[d.Name]; ok {
+				continue
+			}
+			seen[d.Name] = struct{}{}
+			asset.dependencies = append(asset.dependencies, d.Name)
+		}
+	}
+	addDependencies(metadata.Dependencies)
+	if chartLock != nil {
+		lock := &chart.Lock{}
+		if err := sigyaml.Unmarshal(chartLock, lock); err != nil {
+			return nil, fmt.Errorf("error: failed to decode the Chart.lock of [%s]: %w", p, err)
+		}
+		addDependencies(lock.Dependencies)
+	}
+	return asset, nil
+}
+
+// generateIndex builds the index helm repo index would write for the chart archives in dir of fsys and the
+// directories right below it, adding the charts unpacked under dir that have no archive, e.g.
+// charts/<chart>/<version>/Chart.yaml. Archives that cannot be read are not charts, like helm assumes
+func generateIndex(fsys fs.FS, dir string) (*repo.IndexFile, error) {
+	index := repo.NewIndexFile()
+	var archives []string
+	for _, pattern := range []string{"*.tgz", "*/*.tgz"} {
+		matches, err := fs.Glob(fsys, path.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, matches...)
+	}
+	for _, archive := range archives {
+		asset, err := readArchive(fsys, archive)
+		if err != nil {
+			continue
+		}
+		digest, err := digestFile(fsys, archive)
+		if err != nil {
+			return nil, err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(archive, dir), "/")
+		if err := index.MustAdd(asset.metadata, path.Base(rel), path.Dir(rel), digest); err != nil {
+			return nil, fmt.Errorf("error: failed to index [%s]: %w", archive, err)
+		}
+	}
+	charts, err := loadPackageCharts(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range charts {
+		if index.Has(c.metadata.Name, c.metadata.Version) {
+			continue
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(path.Dir(c.path), dir), "/")
+		if rel == "" {
+			rel = "."
+		}
+		if err := index.MustAdd(c.metadata, fmt.Sprintf("%s-%s.tgz", c.metadata.Name, c.metadata.Version), rel, ""); err != nil {
+			return nil, fmt.Errorf("error: failed to index [%s]: %w", c.path, err)
+		}
+	}
+	index.SortEntries()
+	return index, nil
+}
+
+// digestFile returns the sha256 of a file the way helm writes it in the digest of index entries
+func digestFile(fsys fs.FS, name string) (string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// localFS returns a file system of the local disk holding p and the name of p inside it. Relative paths are
+// resolved from the current directory so the names found under p start like p does, other paths from their parent
+func localFS(p string) (fs.FS, string) {
+	if name := filepath.ToSlash(filepath.Clean(p)); fs.ValidPath(name) {
+		return os.DirFS("."), name
+	}
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	return os.DirFS(filepath.Dir(p)), filepath.ToSlash(filepath.Base(p))
+}
+
+// localPath returns the path on disk of the name found in the file system of localFS(p)
+func localPath(p, name string) string {
+	if fs.ValidPath(filepath.ToSlash(filepath.Clean(p))) {
+		return filepath.FromSlash(name)
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.Join(filepath.Dir(p), filepath.FromSlash(name))
+	}
+	found := filepath.Join(filepath.Dir(abs), filepath.FromSlash(name))
+	// Paths like ../assets stay relative to the current directory
+	if !filepath.IsAbs(p) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, found); err == nil {
+				return rel
+			}
+		}
+	}
+	return found
+}
+
+// localPathError names the file of a *fs.PathError returned by the file system of localFS(p) by its path on disk
+func localPathError(err error, p string) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		pathErr.Path = localPath(p, pathErr.Path)
+	}
+	return err
+}
+
+// validateChartFiles validates the archives under assetsDir and the chart directories under packagesDir against the
+// maintainers file, either directory is skipped if it is empty
+func validateChartFiles(ctx context.Context, maintainersFilePath, assetsDir, packagesDir string) error {
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	var assets, all []*chartAsset
+	if assetsDir != "" {
+		fsys, dir := localFS(assetsDir)
+		if assets, err = loadChartAssets(fsys, dir); err != nil {
+			return localPathError(err, assetsDir)
+		}
+		for _, asset := range assets {
+			asset.path = localPath(assetsDir, asset.path)
+		}
+		all = append(all, assets...)
+	}
+	if packagesDir != "" {
+		fsys, dir := localFS(packagesDir)
+		charts, err := loadPackageCharts(fsys, dir)
+		if err != nil {
+			return localPathError(err, packagesDir)
+		}
+		for _, chart := range charts {
+			chart.path = localPath(packagesDir, chart.path)
+		}
+		all = append(all, charts...)
+	}
+	for _, problem := range validateAssets(maintainers, assets, maintainersFilePath) {
+		fmt.Println(problem)
+	}
+	for _, problem := range validateAnnotations(maintainers, all) {
+		fmt.Println(problem)
+	}
+	for _, problem := range validateDependencies(maintainers, all) {
+		fmt.Println(problem)
+	}
+	return nil
+}
+
+// latestAssets returns the latest version of every chart, keyed by name, and the sorted chart names
+func latestAssets(assets []*chartAsset) (map[string]*chartAsset, []string) {
+	latest := make(map[string]*chartAsset)
+	var names []string
+	for _, asset := range assets {
+		name := asset.metadata.Name
+		previous, ok := latest[name]
+		if !ok {
+			names = append(names, name)
+		}
+		if !ok || newerVersion(asset.metadata.Version, previous.metadata.Version) {
+			latest[name] = asset
+		}
+	}
+	sort.Strings(names)
+	return latest, names
+}
+
+// validateAssets compares the metadata packaged in chart archives with the maintainers file, reporting packaged
+// charts no team maintains and maintained charts whose latest archive is deprecated
+func validateAssets(maintainers Maintainers, assets []*chartAsset, maintainersFilePath string) []string {
+	var problems []string
+	latest, names := latestAssets(assets)
+	for _, name := range names {
+		asset := latest[name]
+		m, _ := maintainers.FindChartVersion(name, asset.metadata.Version)
+		if m == nil {
+			problems = append(problems, fmt.Sprintf("error: packaged chart [%s] in [%s] is missing from maintainers file [%s]", name, asset.path, maintainersFilePath))
+			continue
+		}
+		if asset.metadata.Deprecated {
+			problems = append(problems, fmt.Sprintf("warning: chart [%s] is deprecated in [%s] but still maintained by [%s]", name, asset.path, m.Name))
+		}
+	}
+	return problems
+}
+
+// validateAnnotations checks the maintainer team annotation of the latest version of every chart names its owning
+// team, charts without the annotation are not checked
+func validateAnnotations(maintainers Maintainers, assets []*chartAsset) []string {
+	var problems []string
+	latest, names := latestAssets(assets)
+	for _, name := range names {
+		asset := latest[name]
+		if problem := validate.CheckMaintainerTeamAnnotation(maintainers, name, asset.metadata.Version, asset.metadata.Annotations, asset.path); problem != nil {
+			problems = append(problems, problem.String())
+		}
+	}
+	return problems
+}
+
+// validateDependencies warns about charts depending on a chart of the maintainers file owned by another team,
+// unless the dependency is listed in the chart's acknowledgedDependencies
+func validateDependencies(maintainers Maintainers, assets []*chartAsset) []string {
+	var problems []string
+	latest, names := latestAssets(assets)
+	for _, name := range names {
+		asset := latest[name]
+		m, c := maintainers.FindChartVersion(name, asset.metadata.Version)
+		if m == nil {
+			continue
+		}
+		for _, dependency := range asset.dependencies {
+			owner, _ := maintainers.FindChart(dependency)
+			if owner == nil || owner.Name == m.Name || c.Acknowledges(dependency) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("warning: chart [%s] maintained by [%s] depends on chart [%s] maintained by [%s], add it to acknowledgedDependencies if this is intended", name, m.Name, dependency, owner.Name))
+		}
+	}
+	return problems
+}
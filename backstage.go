@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// backstageEntity is a Backstage catalog entity, see https://backstage.io/docs/features/software-catalog/descriptor-format
+type backstageEntity struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   backstageMetadata      `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+type backstageMetadata struct {
+	Name        string            `yaml:"name"`
+	Title       string            `yaml:"title,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Links       []backstageLink   `yaml:"links,omitempty"`
+}
+
+type backstageLink struct {
+	URL   string `yaml:"url"`
+	Title string `yaml:"title,omitempty"`
+}
+
+// backstageNameInvalid matches the runs of characters a Backstage entity name cannot hold
+var backstageNameInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+func runGenerateBackstage(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		output              string
+		lifecycle           string
+		system              string
+	)
+	fs := flag.NewFlagSet("generate backstage", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&output, "o", "-", "path the catalog-info.yaml is written to, - for stdout")
+	fs.StringVar(&lifecycle, "lifecycle", "production", "lifecycle of the chart components")
+	fs.StringVar(&system, "system", "", "if set, the system the chart components are part of")
+	fs.Parse(args)
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	data, err := encodeBackstageCatalog(backstageEntities(maintainers, lifecycle, system))
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote [%s]\n", output)
+	return nil
+}
+
+// backstageEntities returns a Group per team and a Component per chart owned by the Group of its team, a chart
+// maintained by several teams for different versions is owned by the first one
+func backstageEntities(maintainers Maintainers, lifecycle, system string) []backstageEntity {
+	var entities []backstageEntity
+	seen := make(map[string]struct{})
+	for _, m := range maintainers {
+		group := backstageEntity{
+			APIVersion: "backstage.io/v1alpha1",
+			Kind:       "Group",
+			Metadata:   backstageMetadata{Name: backstageName(m.Name), Title: m.Name},
+			Spec:       map[string]interface{}{"type": "team", "children": []string{}},
+		}
+		if m.Contact.Email != "" {
+			group.Spec["profile"] = map[string]string{"displayName": m.Name, "email": m.Contact.Email}
+		}
+		if m.Contact.URL != "" {
+			group.Metadata.Links = append(group.Metadata.Links, backstageLink{URL: m.Contact.URL, Title: "Team page"})
+		}
+		if m.Contact.SlackChannel != "" {
+			group.Metadata.Annotations = map[string]string{"slack.com/channel": strings.TrimPrefix(m.Contact.SlackChannel, "#")}
+		}
+		entities = append(entities, group)
+	}
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			if _, ok := seen[chart.Name]; ok {
+				continue
+			}
+			seen[chart.Name] = struct{}{}
+			component := backstageEntity{
+				APIVersion: "backstage.io/v1alpha1",
+				Kind:       "Component",
+				Metadata: backstageMetadata{
+					Name:        backstageName(chart.Name),
+					Title:       chart.Name,
+					Annotations: map[string]string{validate.MaintainerTeamAnnotation: m.Name},
+				},
+				Spec: map[string]interface{}{
+					"type":      "helm-chart",
+					"lifecycle": lifecycle,
+					"owner":     "group:default/" + backstageName(m.Name),
+				},
+			}
+			if system != "" {
+				component.Spec["system"] = system
+			}
+			entities = append(entities, component)
+		}
+	}
+	return entities
+}
+
+// backstageName turns a team or chart name into a valid entity name, e.g. neo-engineering-team-team-area1 for
+// "Neo Engineering Team (team/area1)"
+func backstageName(name string) string {
+	name = strings.Trim(backstageNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// encodeBackstageCatalog writes the entities as the documents of a single catalog-info.yaml
+func encodeBackstageCatalog(entities []backstageEntity) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, entity := range entities {
+		if err := enc.Encode(entity); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runBlame(ctx context.Context, args []string) error {
+	var maintainersFilePath string
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("blame", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file, which must be tracked by git")
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" {
+		return errors.New("error: usage: cowhand blame <chart>")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	owner, _ := maintainers.FindChart(chartName)
+	if owner == nil {
+		return fmt.Errorf("error: chart [%s] is not in maintainers file [%s]", chartName, maintainersFilePath)
+	}
+	revisions, err := maintainersRevisions(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	// Walk back while the chart stays with its current team, the last revision reached is the one that gave it to them
+	var since *gitRevision
+	for i := range revisions {
+		atRevision, err := maintainersAtRevision(revisions[i], maintainersFilePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v, stopping there\n", err)
+			break
+		}
+		if m, _ := atRevision.FindChart(chartName); m == nil || m.Name != owner.Name {
+			break
+		}
+		since = &revisions[i]
+	}
+	if since == nil {
+		fmt.Printf("chart [%s] is maintained by [%s] since changes to [%s] that are not committed yet\n", chartName, owner.Name, maintainersFilePath)
+		return nil
+	}
+	fmt.Printf("chart [%s] is maintained by [%s] since commit %s\n", chartName, owner.Name, since.short())
+	fmt.Printf("author:  %s <%s>\n", since.author, since.email)
+	fmt.Printf("date:    %s\n", since.date.Format("2006-01-02 15:04:05 -0700"))
+	fmt.Printf("subject: %s\n", since.subject)
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// branchOwnership is the index and maintainers file of one branch
+type branchOwnership struct {
+	branch      string
+	index       *repo.IndexFile
+	maintainers Maintainers
+}
+
+// fetchBranches fetches every branch of remote in a single git fetch, which writes FETCH_HEAD and locks the
+// repository anyway, and returns the commit of each branch so that their files can then be read concurrently
+func fetchBranches(ctx context.Context, remote string, branches []string) ([]string, error) {
+	args := append([]string{"fetch", "--quiet", "--depth=1", remote}, branches...)
+	if out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("error: failed to fetch branches [%s] of [%s]: %s", strings.Join(branches, ", "), remote, strings.TrimSpace(string(out)))
+	}
+	path, err := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", "FETCH_HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to locate FETCH_HEAD: %w", err)
+	}
+	data, err := os.ReadFile(strings.TrimSpace(string(path)))
+	if err != nil {
+		return nil, err
+	}
+	// FETCH_HEAD holds a line per fetched branch, in the order they were given
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(branches) {
+		return nil, fmt.Errorf("error: fetching branches [%s] of [%s] returned %s", strings.Join(branches, ", "), remote, pluralize(len(lines), "ref"))
+	}
+	commits := make([]string, len(branches))
+	for i, line := range lines {
+		commits[i], _, _ = strings.Cut(line, "\t")
+	}
+	return commits, nil
+}
+
+// gitShow returns the contents of path at commit, a commit of branch of remote fetched by fetchBranches
+func gitShow(ctx context.Context, remote, branch, commit, path string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "show", commit+":"+path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read [%s] on branch [%s] of [%s]: %s", path, branch, remote, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// loadBranches reads the index of every branch, and its maintainers file if branchMaintainers is set, otherwise
+// every branch is checked against maintainers. The branches are fetched at once, then up to workers branches are
+// read from git and decoded concurrently
+func loadBranches(ctx context.Context, remote string, branches []string, indexPath, maintainersPath string, branchMaintainers bool, maintainers Maintainers, workers int) ([]*branchOwnership, error) {
+	commits, err := fetchBranches(ctx, remote, branches)
+	if err != nil {
+		return nil, err
+	}
+	loaded := make([]*branchOwnership, len(branches))
+	errs := make([]error, len(branches))
+	parallel(len(branches), workers, func(i int) {
+		loaded[i], errs[i] = loadBranch(ctx, remote, branches[i], commits[i], indexPath, maintainersPath, branchMaintainers, maintainers)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return loaded, nil
+}
+
+func loadBranch(ctx context.Context, remote, branch, commit, indexPath, maintainersPath string, branchMaintainers bool, maintainers Maintainers) (*branchOwnership, error) {
+	data, err := gitShow(ctx, remote, branch, commit, indexPath)
+	if err != nil {
+		return nil, err
+	}
+	index, err := decodeIndex(data, branch+":"+indexPath)
+	if err != nil {
+		return nil, err
+	}
+	b := &branchOwnership{branch: branch, index: index, maintainers: maintainers}
+	if branchMaintainers {
+		data, err := gitShow(ctx, remote, branch, commit, maintainersPath)
+		if err != nil {
+			return nil, err
+		}
+		b.maintainers = nil
+		if err := decodeYAMLFile(bytes.NewReader(data), &b.maintainers); err != nil {
+			return nil, fmt.Errorf("error: failed to decode [%s] on branch [%s]: %w", maintainersPath, branch, err)
+		}
+	}
+	return b, nil
+}
+
+// validateBranches prints the charts whose ownership differs between branches, the maintainers file is read from
+// each branch at maintainersFilePath when branchMaintainers is set and from the local file otherwise
+func validateBranches(ctx context.Context, remote string, branches []string, indexPath, maintainersFilePath string, branchMaintainers bool, workers int) error {
+	var maintainers Maintainers
+	if !branchMaintainers {
+		var err error
+		if maintainers, err = decodeMaintainersFile(ctx, maintainersFilePath); err != nil {
+			return err
+		}
+	}
+	loaded, err := loadBranches(ctx, remote, branches, indexPath, strings.TrimPrefix(maintainersFilePath, "./"), branchMaintainers, maintainers, workers)
+	if err != nil {
+		return err
+	}
+	for _, problem := range compareBranchOwnership(loaded) {
+		fmt.Println(problem)
+	}
+	return nil
+}
+
+// compareBranchOwnership returns a problem for every chart that is not maintained by the same team on every branch
+// whose index holds it, listing the owner on each of those branches
+func compareBranchOwnership(branches []*branchOwnership) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, b := range branches {
+		for _, name := range validate.IndexChartNames(b.index) {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	var problems []string
+	for _, name := range names {
+		var owners []string
+		var first string
+		consistent := true
+		for _, b := range branches {
+			cv := validate.LatestChartVersion(b.index, name)
+			if cv == nil {
+				if _, ok := b.index.Entries[name]; !ok {
+					continue
+				}
+				cv = b.index.Entries[name][0]
+			}
+			owner := "unowned"
+			if m, _ := b.maintainers.FindChartVersion(name, cv.Version); m != nil {
+				owner = m.Name
+			}
+			if len(owners) == 0 {
+				first = owner
+			} else if owner != first {
+				consistent = false
+			}
+			owners = append(owners, fmt.Sprintf("%s [%s]", b.branch, owner))
+		}
+		if !consistent {
+			problems = append(problems, fmt.Sprintf("error: chart [%s] is not owned consistently across branches: %s", name, strings.Join(owners, ", ")))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cachingTransport stores GET responses and GraphQL query results on disk and serves them until ttl expires, after
+// which they are revalidated with If-None-Match/If-Modified-Since. GitHub does not count
+// 304 responses against the rate limit, so revalidated reads are effectively free
+type cachingTransport struct {
+	dir string
+	ttl time.Duration
+	// refresh ignores the stored entries, the responses are still stored for the next run
+	refresh bool
+	// cacheControl also serves the entries for as long as the Cache-Control or Expires headers of the server allow,
+	// and does not store the responses it forbids storing
+	cacheControl bool
+	transport    http.RoundTripper
+}
+
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	// Body is the name of the file next to the entry holding the body, so that a revalidation only rewrites the
+	// entry however large the body
+	Body         string    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	// Expires is until when the server allows serving the entry without revalidating it, with cacheControl
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+func newCachingTransport(dir string, ttl time.Duration) *cachingTransport {
+	return &cachingTransport{
+		dir:       dir,
+		ttl:       ttl,
+		transport: tracingTransport(http.DefaultTransport),
+	}
+}
+
+// defaultCacheDir returns the directory cowhand uses for cached API reads, or an empty string if
+// the user cache directory cannot be determined
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cowhand")
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.dir == "" {
+		return t.transport.RoundTrip(req)
+	}
+	graphql := isGraphQLQuery(req)
+	if req.Method != http.MethodGet && !graphql {
+		resp, err := t.transport.RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			t.markMutated()
+		}
+		return resp, err
+	}
+	var requestBody []byte
+	if graphql {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		requestBody = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	path := t.entryPath(req, requestBody)
+	var entry *cacheEntry
+	if !t.refresh {
+		entry, _ = t.load(path)
+	}
+	if entry != nil && (time.Since(entry.FetchedAt) < t.ttl || t.cacheControl && time.Now().Before(entry.Expires)) && entry.FetchedAt.After(t.lastMutation()) {
+		if resp, err := t.response(entry, req); err == nil {
+			return resp, nil
+		}
+		entry = nil
+	}
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if graphql {
+			req.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.FetchedAt = time.Now()
+		// A 304 carries the caching headers of the entry again, possibly changed
+		expires, store := t.expiry(resp.Header, entry.FetchedAt)
+		entry.Expires = expires
+		cached, err := t.response(entry, req)
+		if err != nil {
+			t.remove(path, entry)
+			return nil, err
+		}
+		if store {
+			t.save(path, entry)
+		} else {
+			t.remove(path, entry)
+		}
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	now := time.Now()
+	expires, store := t.expiry(resp.Header, now)
+	if !store {
+		if entry != nil {
+			t.remove(path, entry)
+		}
+		return resp, nil
+	}
+	previous := entry
+	entry = &cacheEntry{
+		URL:          req.URL.String(),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    now,
+		Expires:      expires,
+	}
+	saved := func(body string) {
+		entry.Body = body
+		if !t.save(path, entry) {
+			os.Remove(filepath.Join(t.dir, body))
+			return
+		}
+		if previous != nil && previous.Body != body {
+			os.Remove(filepath.Join(t.dir, previous.Body))
+		}
+	}
+	// GraphQL results are small and carry their errors in a 200, they are read whole to leave the errors out
+	if graphql {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if graphqlFailed(body) {
+			return resp, nil
+		}
+		if name, ok := t.saveBody(path, body); ok {
+			saved(name)
+		}
+		return resp, nil
+	}
+	// Other bodies, e.g. an index of a hundred megabytes, are written to the cache as the caller reads them
+	file, err := t.create(bodyPattern(path))
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body = &cachingBody{body: resp.Body, file: file, saved: saved}
+	return resp, nil
+}
+
+// expiry returns until when the response can be served without revalidating it, from its max-age or else its
+// Expires header minus the time it already spent in shared caches, and whether it can be stored at all: responses
+// marked no-store or private, e.g. files only readable with a token, are not written to disk. Without cacheControl
+// every response is stored, and expires right away
+func (t *cachingTransport) expiry(header http.Header, now time.Time) (expires time.Time, store bool) {
+	if !t.cacheControl {
+		return time.Time{}, true
+	}
+	var maxAge time.Duration
+	hasMaxAge := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store", "private":
+			return time.Time{}, false
+		case "no-cache":
+			return time.Time{}, true
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge, hasMaxAge = time.Duration(seconds)*time.Second, true
+			}
+		}
+	}
+	if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+		now = now.Add(-time.Duration(age) * time.Second)
+	}
+	if hasMaxAge {
+		return now.Add(maxAge), true
+	}
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		if date, err := http.ParseTime(header.Get("Date")); err == nil {
+			// Expires is relative to the clock of the server
+			return time.Now().Add(expires.Sub(date)), true
+		}
+		return expires, true
+	}
+	return time.Time{}, true
+}
+
+// isGraphQLQuery reports whether req is a GraphQL POST, cowhand only sends queries over GraphQL so these are
+// cached like GET requests and never count as mutations
+func isGraphQLQuery(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/graphql") && req.Body != nil
+}
+
+// graphqlFailed reports whether a GraphQL response carries errors, these are returned with a 200 status but must
+// not be cached
+func graphqlFailed(body []byte) bool {
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return true
+	}
+	_, ok := resp["errors"]
+	return ok
+}
+
+// markMutated records that a write went through so entries fetched before it are revalidated instead of
+// served blindly, otherwise a rerun right after creating issues would not see them and create duplicates
+func (t *cachingTransport) markMutated() {
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return
+	}
+	os.WriteFile(filepath.Join(t.dir, ".mutated"), nil, 0o600)
+	now := time.Now()
+	os.Chtimes(filepath.Join(t.dir, ".mutated"), now, now)
+}
+
+func (t *cachingTransport) lastMutation() time.Time {
+	info, err := os.Stat(filepath.Join(t.dir, ".mutated"))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// entryPath keys entries by URL and every request header, which hold the credentials, so responses fetched with one
+// token are never served to a different one whatever header carries it
+func (t *cachingTransport) entryPath(req *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.String())
+	h.Write(body)
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		io.WriteString(h, "\n"+name+": "+strings.Join(req.Header[name], ", "))
+	}
+	return filepath.Join(t.dir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+func (t *cachingTransport) load(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	// An entry whose body went missing, e.g. cleaned up with the cache, is fetched again without its validators
+	if entry.Body == "" || entry.Body != filepath.Base(entry.Body) {
+		return nil, fmt.Errorf("cache entry [%s] has no body", path)
+	}
+	if _, err := os.Stat(filepath.Join(t.dir, entry.Body)); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// save writes the entry atomically and reports whether it did; failures are otherwise ignored since the cache is
+// only an optimization
+func (t *cachingTransport) save(path string, entry *cacheEntry) bool {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return false
+	}
+	tmp, err := t.create(".entry-*")
+	if err != nil {
+		return false
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false
+	}
+	if err := tmp.Close(); err != nil {
+		return false
+	}
+	return os.Rename(tmp.Name(), path) == nil
+}
+
+// saveBody writes the body of the entry at path to a new file and returns its name. Every body gets a file of its
+// own, so an entry never names a body that is only partly written, or the body of a newer response
+func (t *cachingTransport) saveBody(path string, body []byte) (string, bool) {
+	file, err := t.create(bodyPattern(path))
+	if err != nil {
+		return "", false
+	}
+	if _, err := file.Write(body); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", false
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", false
+	}
+	return filepath.Base(file.Name()), true
+}
+
+// bodyPattern is the pattern of the names of the bodies of the entry at path
+func bodyPattern(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".json") + "-*.body"
+}
+
+// create creates a new file in the cache directory from pattern, like os.CreateTemp
+func (t *cachingTransport) create(pattern string) (*os.File, error) {
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(t.dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	// Entries hold what was fetched with a token, e.g. a private maintainers file, only the user can read them
+	if err := file.Chmod(0o600); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// remove deletes the entry at path and its body
+func (t *cachingTransport) remove(path string, entry *cacheEntry) {
+	os.Remove(path)
+	if entry.Body != "" {
+		os.Remove(filepath.Join(t.dir, entry.Body))
+	}
+}
+
+// response is the cached response of the entry, it fails if its body cannot be read
+func (t *cachingTransport) response(e *cacheEntry, req *http.Request) (*http.Response, error) {
+	body, err := os.Open(filepath.Join(t.dir, e.Body))
+	if err != nil {
+		return nil, err
+	}
+	info, err := body.Stat()
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	header := e.Header.Clone()
+	header.Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          body,
+		ContentLength: info.Size(),
+		Request:       req,
+	}, nil
+}
+
+// cachingBody writes the body of a response to file as it is read, and calls saved with the name of the file once
+// the body is read to its end. A body closed before its end is left out of the cache, and failing to write it only
+// leaves it out of the cache too
+type cachingBody struct {
+	body  io.ReadCloser
+	file  *os.File
+	saved func(name string)
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if b.file == nil {
+		return n, err
+	}
+	if _, werr := b.file.Write(p[:n]); werr != nil {
+		b.discard()
+		return n, err
+	}
+	if err == io.EOF {
+		file := b.file
+		b.file = nil
+		if file.Close() != nil {
+			os.Remove(file.Name())
+			return n, err
+		}
+		b.saved(filepath.Base(file.Name()))
+	}
+	return n, err
+}
+
+func (b *cachingBody) Close() error {
+	b.discard()
+	return b.body.Close()
+}
+
+// discard removes the body written so far
+func (b *cachingBody) discard() {
+	if b.file != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+		b.file = nil
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachingTransportExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		header  http.Header
+		expires time.Time
+		store   bool
+	}{
+		{name: "no cache headers", header: http.Header{}, store: true},
+		{name: "max-age", header: http.Header{"Cache-Control": {"public, max-age=60"}}, expires: now.Add(time.Minute), store: true},
+		{name: "max-age minus age", header: http.Header{"Cache-Control": {"max-age=60"}, "Age": {"20"}}, expires: now.Add(40 * time.Second), store: true},
+		{name: "max-age over expires", header: http.Header{"Cache-Control": {"max-age=60"}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, expires: now.Add(time.Minute), store: true},
+		{name: "no-cache", header: http.Header{"Cache-Control": {"no-cache, max-age=60"}}, store: true},
+		{name: "no-store", header: http.Header{"Cache-Control": {"max-age=60, no-store"}}},
+		{name: "private", header: http.Header{"Cache-Control": {"Private, max-age=60"}}},
+		{name: "expires", header: http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, expires: now.Add(time.Hour), store: true},
+		{name: "invalid expires", header: http.Header{"Expires": {"0"}}, store: true},
+	}
+	transport := &cachingTransport{cacheControl: true}
+	for _, tt := range tests {
+		expires, store := transport.expiry(tt.header, now)
+		if !expires.Equal(tt.expires) || store != tt.store {
+			t.Errorf("%s: got %s and store %v, want %s and store %v", tt.name, expires, store, tt.expires, tt.store)
+		}
+	}
+	// Without cacheControl the headers are ignored, every response is stored and revalidated
+	if expires, store := (&cachingTransport{}).expiry(http.Header{"Cache-Control": {"no-store, max-age=60"}}, now); !expires.IsZero() || !store {
+		t.Errorf("without cacheControl: got %s and store %v", expires, store)
+	}
+}
+
+// cacheTestServer serves an index with an ETag and the Cache-Control header of the cache-control query parameter,
+// and counts the requests it gets and how many it answered with a 304
+func cacheTestServer(t *testing.T) (server *httptest.Server, requests, notModified *atomic.Int32) {
+	requests, notModified = new(atomic.Int32), new(atomic.Int32)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", r.URL.Query().Get("cache-control"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, "apiVersion: v1\n")
+	}))
+	t.Cleanup(server.Close)
+	return server, requests, notModified
+}
+
+func cacheTestGet(t *testing.T, transport *cachingTransport, url string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "apiVersion: v1\n" {
+		t.Fatalf("got body %q", body)
+	}
+}
+
+func TestCachingTransportRevalidates(t *testing.T) {
+	server, requests, notModified := cacheTestServer(t)
+	// Without a TTL every read after the first is revalidated with the ETag of the cached entry
+	transport := newCachingTransport(t.TempDir(), 0)
+	for i := 0; i < 3; i++ {
+		cacheTestGet(t, transport, server.URL+"/index.yaml")
+	}
+	if requests.Load() != 3 || notModified.Load() != 2 {
+		t.Errorf("got %d requests and %d 304s, want 3 and 2", requests.Load(), notModified.Load())
+	}
+	// With a TTL the cached entry is used as is until it expires
+	requests.Store(0)
+	transport = newCachingTransport(t.TempDir(), time.Hour)
+	for i := 0; i < 3; i++ {
+		cacheTestGet(t, transport, server.URL+"/index.yaml")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("got %d requests within the TTL, want 1", requests.Load())
+	}
+}
+
+func TestCachingTransportCacheControl(t *testing.T) {
+	server, requests, notModified := cacheTestServer(t)
+	tests := []struct {
+		name         string
+		cacheControl string
+		// requests and notModified are the requests the server got for three reads, and how many it answered with a 304
+		requests    int32
+		notModified int32
+		stored      bool
+	}{
+		{name: "revalidated", requests: 3, notModified: 2, stored: true},
+		{name: "fresh", cacheControl: "max-age=3600", requests: 1, stored: true},
+		{name: "no-store", cacheControl: "no-store", requests: 3},
+		{name: "private", cacheControl: "private, max-age=3600", requests: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			notModified.Store(0)
+			transport := newCachingTransport(t.TempDir(), 0)
+			transport.cacheControl = true
+			for i := 0; i < 3; i++ {
+				cacheTestGet(t, transport, server.URL+"/index.yaml?cache-control="+url.QueryEscape(tt.cacheControl))
+			}
+			if requests.Load() != tt.requests || notModified.Load() != tt.notModified {
+				t.Errorf("got %d requests and %d 304s, want %d and %d", requests.Load(), notModified.Load(), tt.requests, tt.notModified)
+			}
+			entries, err := os.ReadDir(transport.dir)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if stored := len(entries) > 0; stored != tt.stored {
+				t.Fatalf("got stored %v, want %v", stored, tt.stored)
+			}
+			for _, entry := range entries {
+				if info, err := entry.Info(); err != nil || info.Mode().Perm() != 0o600 {
+					t.Errorf("cache entry [%s] is not only readable by the user: %v", entry.Name(), info.Mode())
+				}
+			}
+		})
+	}
+}
+
+func TestCachingTransportStoresBodiesApart(t *testing.T) {
+	var version atomic.Value
+	version.Store("v1")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + version.Load().(string) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		io.WriteString(w, "apiVersion: "+version.Load().(string)+"\n")
+	}))
+	defer server.Close()
+	transport := newCachingTransport(t.TempDir(), 0)
+	get := func(want string) {
+		t.Helper()
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL + "/index.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if body, _ := io.ReadAll(resp.Body); string(body) != want {
+			t.Fatalf("got body %q, want %q", body, want)
+		}
+	}
+	// files returns the modification time of every file of the cache by its name
+	files := func() map[string]time.Time {
+		t.Helper()
+		entries, err := os.ReadDir(transport.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := make(map[string]time.Time)
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				t.Fatal(err)
+			}
+			found[entry.Name()] = info.ModTime()
+		}
+		return found
+	}
+	get("apiVersion: v1\n")
+	first := files()
+	if len(first) != 2 {
+		t.Fatalf("got cache files %v, want an entry and its body", first)
+	}
+	// A revalidation rewrites the entry only, the body is left as it is
+	time.Sleep(10 * time.Millisecond)
+	get("apiVersion: v1\n")
+	second := files()
+	for name, modTime := range first {
+		isEntry := filepath.Ext(name) == ".json"
+		if _, ok := second[name]; !ok || second[name].Equal(modTime) == isEntry {
+			t.Errorf("revalidation changed the cache files from %v to %v", first, second)
+		}
+	}
+	// A new body replaces the previous one
+	version.Store("v2")
+	get("apiVersion: v2\n")
+	third := files()
+	for name := range first {
+		if _, ok := third[name]; ok != (filepath.Ext(name) == ".json") {
+			t.Errorf("new body did not replace the previous one, got cache files %v", third)
+		}
+	}
+	if len(third) != 2 {
+		t.Errorf("got cache files %v, want an entry and its body", third)
+	}
+}
+
+func TestCachingTransportStoresReadBodies(t *testing.T) {
+	server, requests, _ := cacheTestServer(t)
+	transport := newCachingTransport(t.TempDir(), time.Hour)
+	client := &http.Client{Transport: transport}
+	// A body closed before its end is not stored
+	resp, err := client.Get(server.URL + "/index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadFull(resp.Body, make([]byte, 4))
+	resp.Body.Close()
+	if entries, _ := os.ReadDir(transport.dir); len(entries) != 0 {
+		t.Fatalf("stored a body closed before its end: %v", entries)
+	}
+	// A body read to its end is, and the next read is served from the stored file
+	cacheTestGet(t, transport, server.URL+"/index.yaml")
+	resp, err = client.Get(server.URL + "/index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, ok := resp.Body.(*os.File); !ok || resp.ContentLength != int64(len("apiVersion: v1\n")) {
+		t.Errorf("cached response has body %T of length %d", resp.Body, resp.ContentLength)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("got %d requests, want 2", requests.Load())
+	}
+}
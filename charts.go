@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// teamChart is a chart of charts --output json, Team is only set when the charts are not all of the same team
+type teamChart struct {
+	Name               string   `json:"name"`
+	Team               string   `json:"team,omitempty"`
+	GenerateIssue      bool     `json:"generateIssue"`
+	GithubLabels       []string `json:"githubLabels"`
+	MaintainedVersions string   `json:"maintainedVersions,omitempty"`
+	Repositories       []string `json:"repositories,omitempty"`
+}
+
+func runCharts(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		team                string
+		label               string
+		output              string
+		tmpl                queryTemplateFlag
+	)
+	fs := flag.NewFlagSet("charts", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&team, "team", "", "only list the charts of this team, or of the team a unique part of its name matches such as team/area1")
+	fs.StringVar(&label, "label", "", "only list the charts carrying this GitHub label, e.g. area/monitoring")
+	fs.StringVar(&output, "output", "text", "output format, text or json")
+	tmpl.register(fs)
+	fs.Parse(args)
+	if team == "" && label == "" {
+		return errors.New("error: usage: cowhand charts [--team <name>] [--label <label>] [--output text|json]")
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("error: unknown output [%s], use text or json", output)
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	teams := maintainers
+	if team != "" {
+		m, err := maintainers.FindTeam(team)
+		if err != nil {
+			return fmt.Errorf("error: %w", err)
+		}
+		teams = Maintainers{m}
+	}
+	var charts []teamChart
+	var items []queryItem
+	for _, m := range teams {
+		for i, chart := range m.Charts {
+			if label != "" && !containsString(chart.GithubLabels, label) {
+				continue
+			}
+			items = append(items, queryItem{Team: m, Chart: &m.Charts[i]})
+			labels := chart.GithubLabels
+			if labels == nil {
+				labels = []string{}
+			}
+			charts = append(charts, teamChart{
+				Name:               chart.Name,
+				Team:               m.Name,
+				GenerateIssue:      chart.GenerateIssue,
+				GithubLabels:       labels,
+				MaintainedVersions: chart.MaintainedVersions,
+				Repositories:       chart.Repositories,
+			})
+		}
+	}
+	if team != "" {
+		for i := range charts {
+			charts[i].Team = ""
+		}
+	}
+
+	if tmpl.set() {
+		return tmpl.write(os.Stdout, items)
+	}
+	if output == "json" {
+		if charts == nil {
+			charts = []teamChart{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		out := struct {
+			Team   string      `json:"team,omitempty"`
+			Label  string      `json:"label,omitempty"`
+			Charts []teamChart `json:"charts"`
+		}{Label: label, Charts: charts}
+		if team != "" {
+			out.Team = teams[0].Name
+		}
+		return enc.Encode(out)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if team == "" {
+		fmt.Fprintln(w, "CHART\tTEAM\tGENERATE ISSUE\tLABELS")
+	} else {
+		fmt.Fprintln(w, "CHART\tGENERATE ISSUE\tLABELS")
+	}
+	for _, chart := range charts {
+		name := chart.Name
+		if chart.MaintainedVersions != "" {
+			name += " (" + chart.MaintainedVersions + ")"
+		}
+		if team == "" {
+			name += "\t" + chart.Team
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\n", name, chart.GenerateIssue, strings.Join(chart.GithubLabels, ", "))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runCheck(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		configFilePath      string
+	)
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" {
+		return errors.New("error: usage: cowhand check <chart>")
+	}
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	index, err := decodeIndexFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	problems := checkChart(config, maintainers, index, chartName, maintainersFilePath, indexFilePath)
+	failed := false
+	for _, problem := range problems {
+		fmt.Println(problem)
+		failed = failed || strings.HasPrefix(problem, "error:")
+	}
+	// The exit code fails the pre-commit hook, warnings alone do not
+	if failed {
+		return exitCode(1)
+	}
+	return nil
+}
+
+// checkChart returns the problems validate would report about a chart, found by running the rules on the entries
+// of the chart alone in the maintainers file and the index, plus a warning if its crd chart, or the chart of a crd
+// chart, is maintained by another team
+func checkChart(config *Config, maintainers Maintainers, index *repo.IndexFile, chartName, maintainersFilePath, indexFilePath string) []string {
+	canonical := validate.CanonicalChartName(config.Aliases, chartName)
+	var subset Maintainers
+	for _, m := range maintainers {
+		team := &Maintainer{Name: m.Name, Contact: m.Contact}
+		for _, chart := range m.Charts {
+			if validate.CanonicalChartName(config.Aliases, chart.Name) == canonical {
+				team.Charts = append(team.Charts, chart)
+			}
+		}
+		if len(team.Charts) > 0 {
+			subset = append(subset, team)
+		}
+	}
+	subIndex := repo.NewIndexFile()
+	for _, name := range validate.IndexChartNames(index) {
+		if validate.CanonicalChartName(config.Aliases, name) == canonical {
+			subIndex.Entries[name] = index.Entries[name]
+		}
+	}
+	if len(subset) == 0 && len(subIndex.Entries) == 0 {
+		return []string{fmt.Sprintf("error: chart [%s] is neither in maintainers file [%s] nor in index file [%s]", chartName, maintainersFilePath, indexFilePath)}
+	}
+
+	problems := validate.Lint(subset, "").Strings()
+	if len(subIndex.Entries) == 0 {
+		// crossCheckIndex would only report the index as empty, name the chart instead
+		problems = append(problems, fmt.Sprintf("error: chart [%s] does not exist in index file [%s]", chartName, indexFilePath))
+	} else {
+		problems = append(problems, validate.CrossCheckIndex(config.Aliases, config.Suggestions, subset, subIndex, "", maintainersFilePath, indexFilePath).Strings()...)
+	}
+
+	partner := chartName + "-crd"
+	if strings.HasSuffix(chartName, "-crd") {
+		partner = strings.TrimSuffix(chartName, "-crd")
+	}
+	owner, _ := maintainers.FindChart(chartName)
+	if partnerOwner, _ := maintainers.FindChart(partner); owner != nil && partnerOwner != nil && partnerOwner.Name != owner.Name {
+		problems = append(problems, fmt.Sprintf("warning: chart [%s] is maintained by [%s] but [%s] by [%s]", chartName, owner.Name, partner, partnerOwner.Name))
+	}
+	return problems
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+const (
+	defaultMaintainersFile = "./maintainers.yaml"
+	defaultIndexFile       = "./charts/index.yaml"
+	defaultConfigFile      = "./cowhand.yaml"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []*command{
+	{name: "validate", usage: "validate the maintainers file against the chart index (default)", run: runValidate},
+	{name: "check", usage: "validate a single chart of the maintainers file against the index, for pre-commit hooks", run: runCheck},
+	{name: "issues", usage: "create a tracking issue for every chart with generateIssue enabled", run: runIssues},
+	{name: "report", usage: "report on the charts added or updated between two index files", run: runReport},
+	{name: "label-pr", usage: "label a pull request with the GitHub labels of the charts it changes", run: runLabelPR},
+	{name: "who", usage: "print the team, contacts and labels of a chart", run: runWho},
+	{name: "whois", usage: "list the teams and charts of an email, slack channel or url", run: runWhois},
+	{name: "charts", usage: "list the charts of a team or carrying a label with their generateIssue and labels", run: runCharts},
+	{name: "teams", usage: "list every team with its contacts and number of charts", run: runTeams},
+	{name: "orphans", usage: "print the names of the charts of the index missing from the maintainers file, exiting 1 if there are any", run: runOrphans},
+	{name: "search", usage: "search the chart and team names, emails and slack channels of the maintainers file", run: runSearch},
+	{name: "stats", usage: "report chart, label and generateIssue counts per team and the unowned charts of the index", run: runStats},
+	{name: "diff", usage: "report the teams, contacts and charts added, removed, moved or changed between two maintainers files", run: runDiff},
+	{name: "blame", usage: "print the commit and author that gave a chart to its current team, from the git history of the maintainers file", run: runBlame},
+	{name: "history", usage: "print every team that has owned a chart, from the git history of the maintainers file", run: runHistory},
+	{name: "graph", usage: "print the teams and charts of the maintainers file, with their crds and dependencies, as a DOT graph", run: runGraph},
+	{name: "init", usage: "create a maintainers file with every chart of the index under an UNASSIGNED team", run: runInit},
+	{name: "add", usage: "add a team or charts to the maintainers file by answering prompts, with --interactive", run: runAdd},
+	{name: "add-chart", usage: "add a chart to the charts of a team in the maintainers file", run: runAddChart},
+	{name: "remove-chart", usage: "remove a chart from the team maintaining it in the maintainers file", run: runRemoveChart},
+	{name: "move-chart", usage: "move a chart to another team in the maintainers file", run: runMoveChart},
+	{name: "rename-team", usage: "rename a team in the maintainers file, optionally replacing its label on its charts", run: runRenameTeam},
+	{name: "set-contact", usage: "set the email, slack channel or url of a team in the maintainers file", run: runSetContact},
+	{name: "import", usage: "create or update the maintainers file from an ownership csv", run: runImport},
+	{name: "export", usage: "export the charts of the maintainers file with their team and contact as csv, or with the findings and the index as a SQLite database", run: runExport},
+	{name: "labels", usage: "list the GitHub labels with their usage, or add or remove one on every chart of a team or matching a pattern", run: runLabels},
+	{name: "generate", usage: "generate an ownership page, a Backstage catalog or OWNERS files from the maintainers file", run: runGenerate},
+	{name: "split", usage: "split the maintainers file into a file per team that --maintainers-file also accepts", run: runSplit},
+	{name: "merge", usage: "merge several maintainers files into one, failing on conflicting ownership", run: runMerge},
+	{name: "schema", usage: "print the JSON Schema of the maintainers file for editors and schema stores", run: runSchema},
+	{name: "fmt", usage: "rewrite the maintainers file with sorted teams, charts and labels", run: runFmt},
+	{name: "prune", usage: "remove the charts that are deprecated or no longer in the index from the maintainers file", run: runPrune},
+	{name: "sync", usage: "add the charts of the index missing from the maintainers file to the unassigned team, or to their suggested team with --suggest; sync chart-metadata syncs the owning teams into the Chart.yaml of every package", run: runSync},
+	{name: "action", usage: "validate the maintainers file in a GitHub Actions workflow, with annotations, a job summary and outputs", run: runAction},
+	{name: "serve", usage: "run a server validating maintainers files over HTTP and on every push and pull request, and answering ownership queries", run: runServe},
+	{name: "daemon", usage: "validate the maintainers file on an interval, notifying the findings that appear or are resolved", run: runDaemon},
+	{name: "controller", usage: "run a Kubernetes controller validating Maintainers resources, and optionally ConfigMaps, against their Helm repositories", run: runController},
+}
+
+// exitCode ends a command with the code without printing anything, for commands whose output is meant for scripts
+type exitCode int
+
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(c))
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: cowhand <command> [flags]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+}
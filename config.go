@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"path"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// Config holds the cowhand settings that do not belong in the maintainers file
+type Config struct {
+	Repositories []Repository `yaml:"repositories"`
+	// Aliases maps the old name of a renamed chart to its new one
+	Aliases map[string]string `yaml:"aliases"`
+	// UnassignedTeam receives the charts sync adds to the maintainers file, UNASSIGNED if it is not set
+	UnassignedTeam string `yaml:"unassignedTeam"`
+	// Suggestions are tried in order to suggest a team for charts missing from the maintainers file
+	Suggestions []validate.Suggestion `yaml:"suggestions"`
+	// Handles maps team names to the code review handles generate owners writes in the OWNERS files of their charts
+	Handles map[string]TeamHandles `yaml:"handles"`
+	// Notifications are sent by daemon whenever findings appear or are resolved
+	Notifications []Notification `yaml:"notifications"`
+	// TeamSlack routes the findings and tracking issues of every team to its Slack channel with --notify-teams
+	TeamSlack TeamSlack `yaml:"teamSlack"`
+	// TeamNotifications maps team names to where --notify-teams sends their messages instead of their Slack channel,
+	// e.g. a Teams webhook
+	TeamNotifications map[string][]Notification `yaml:"teamNotifications"`
+	// Email is the SMTP server the digests of every team are sent through with --email-teams
+	Email EmailSettings `yaml:"email"`
+	// Critical are patterns, like those of the suggestions, of the charts whose ownership gaps page through
+	// pagerDuty, e.g. rancher-* for a whole tier of charts
+	Critical  []string  `yaml:"critical"`
+	PagerDuty PagerDuty `yaml:"pagerDuty"`
+	// Routes send the findings daemon notifies to further notifications and emails depending on their team, rule,
+	// severity and chart
+	Routes []Route `yaml:"routes"`
+	// Templates override the messages of the slack, teams, discord and email notifications
+	Templates NotificationTemplates `yaml:"templates"`
+	// ValidateIndexURLs are the indexes and chart repositories the requests of POST /v1/validate can name, requests
+	// naming any other URL are refused
+	ValidateIndexURLs []string `yaml:"validateIndexURLs"`
+}
+
+// TeamHandles are the GitHub handles of a team, reviewers default to the approvers
+type TeamHandles struct {
+	Approvers []string `yaml:"approvers"`
+	Reviewers []string `yaml:"reviewers"`
+}
+
+// Repository is a chart repository validated against the charts of the maintainers file that belong to it,
+// charts that do not declare any repositories belong to the first one
+type Repository struct {
+	Name  string `yaml:"name"`
+	Index string `yaml:"index"`
+	// Mirror is an optional copy of the index, e.g. in an air-gapped registry, that must hold the same charts
+	Mirror string `yaml:"mirror,omitempty"`
+}
+
+// loadConfig decodes the config file, a missing file is only an error if it is not the default one
+func loadConfig(configPath string) (*Config, error) {
+	config := &Config{}
+	file, err := os.Open(configPath)
+	if errors.Is(err, os.ErrNotExist) && configPath == defaultConfigFile {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if err := decodeYAMLFile(file, config); err != nil {
+		return nil, fmt.Errorf("error: failed to decode config file [%s]: %w", configPath, err)
+	}
+	seen := make(map[string]struct{})
+	for _, r := range config.Repositories {
+		if r.Name == "" || r.Index == "" {
+			return nil, fmt.Errorf("error: config file [%s] has a repository without a name or index", configPath)
+		}
+		if _, ok := seen[r.Name]; ok {
+			return nil, fmt.Errorf("error: config file [%s] has duplicate repository [%s]", configPath, r.Name)
+		}
+		seen[r.Name] = struct{}{}
+	}
+	for from, to := range config.Aliases {
+		if _, ok := config.Aliases[to]; ok {
+			return nil, fmt.Errorf("error: config file [%s] aliases chart [%s] to [%s] which is itself aliased, alias it to the final name instead", configPath, from, to)
+		}
+	}
+	for i, n := range config.Notifications {
+		if err := n.validate(); err != nil {
+			return nil, fmt.Errorf("error: config file [%s] has notification [%d] %w", configPath, i, err)
+		}
+	}
+	for team, notifications := range config.TeamNotifications {
+		for i, n := range notifications {
+			if err := n.validate(); err != nil {
+				return nil, fmt.Errorf("error: config file [%s] has notification [%d] of team [%s] %w", configPath, i, team, err)
+			}
+		}
+	}
+	if config.Email.Host != "" {
+		if _, err := mail.ParseAddress(config.Email.From); err != nil {
+			return nil, fmt.Errorf("error: config file [%s] has an email host without a valid from address: %w", configPath, err)
+		}
+	}
+	routes := make(map[string]struct{})
+	for i, r := range config.Routes {
+		if err := r.validate(config); err != nil {
+			return nil, fmt.Errorf("error: config file [%s] has route [%d] %w", configPath, i, err)
+		}
+		if _, ok := routes[r.key(i)]; ok {
+			return nil, fmt.Errorf("error: config file [%s] has duplicate route [%s]", configPath, r.key(i))
+		}
+		routes[r.key(i)] = struct{}{}
+	}
+	if err := config.Templates.parse(); err != nil {
+		return nil, fmt.Errorf("error: config file [%s] has an %w", configPath, err)
+	}
+	for _, pattern := range config.Critical {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("error: config file [%s] has invalid critical pattern [%s]", configPath, pattern)
+		}
+	}
+	for _, s := range config.Suggestions {
+		if _, err := path.Match(s.Pattern, ""); err != nil || s.Team == "" {
+			return nil, fmt.Errorf("error: config file [%s] has suggestion [%s] with an invalid pattern or no team", configPath, s.Pattern)
+		}
+	}
+	return config, nil
+}
+
+// repository returns the repository with the given name, or nil if the config does not define it
+func (c *Config) repository(name string) *Repository {
+	for i := range c.Repositories {
+		if c.Repositories[i].Name == name {
+			return &c.Repositories[i]
+		}
+	}
+	return nil
+}
+
+func (c *Config) unassignedTeam() string {
+	if c.UnassignedTeam == "" {
+		return unassignedTeam
+	}
+	return c.UnassignedTeam
+}
+
+// defaultRepository returns the name of the repository charts without repositories belong to, or "" if there is none
+func (c *Config) defaultRepository() string {
+	if len(c.Repositories) == 0 {
+		return ""
+	}
+	return c.Repositories[0].Name
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+const (
+	controllerGroup = "cowhand.pennyscissors.io"
+	// maintainersLabel marks the ConfigMaps holding a maintainers file in their maintainers.yaml key
+	maintainersLabel = controllerGroup + "/maintainers"
+	// indexAnnotation and clusterRepoAnnotation select the index of a ConfigMap like the index and clusterRepo
+	// fields of the spec of a Maintainers resource
+	indexAnnotation       = controllerGroup + "/index"
+	clusterRepoAnnotation = controllerGroup + "/cluster-repo"
+	conditionValid        = "Valid"
+)
+
+var (
+	maintainersResource = schema.GroupVersionResource{Group: controllerGroup, Version: "v1alpha1", Resource: "maintainers"}
+	configMapResource   = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	// clusterRepoResource is the Helm repository Rancher configures in a cluster
+	clusterRepoResource = schema.GroupVersionResource{Group: "catalog.cattle.io", Version: "v1", Resource: "clusterrepos"}
+)
+
+func runController(ctx context.Context, args []string) error {
+	var (
+		kubeconfig string
+		c          controller
+		configMaps bool
+	)
+	fs := flag.NewFlagSet("controller", flag.ExitOnError)
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "path to the kubeconfig, KUBECONFIG or the in-cluster config if empty")
+	fs.StringVar(&c.namespace, "namespace", "", "namespace watched, every namespace if empty")
+	fs.DurationVar(&c.resync, "resync", 10*time.Minute, "how often every maintainers file is validated again, to catch the changes of the indexes")
+	fs.BoolVar(&configMaps, "configmaps", false, "also validate the ConfigMaps labeled "+maintainersLabel+"=true")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	loading.ExplicitPath = kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("error: failed to load the kubeconfig: %w", err)
+	}
+	if c.client, err = dynamic.NewForConfig(restConfig); err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	broadcaster := record.NewBroadcaster(record.WithContext(ctx))
+	defer broadcaster.Shutdown()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "cowhand"})
+	c.queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[controllerKey]())
+	c.lastState = make(map[types.UID]string)
+
+	informers := []cache.SharedIndexInformer{c.watch(maintainersResource, nil)}
+	if configMaps {
+		informers = append(informers, c.watch(configMapResource, func(o *metav1.ListOptions) { o.LabelSelector = maintainersLabel + "=true" }))
+	}
+	for _, informer := range informers {
+		go informer.Run(ctx.Done())
+		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			return ctx.Err()
+		}
+	}
+	slog.Info("watching maintainers", "namespace", c.namespace, "configmaps", configMaps)
+	go func() {
+		<-ctx.Done()
+		c.queue.ShutDown()
+	}()
+	for c.processNext(ctx) {
+	}
+	return nil
+}
+
+// controller validates the Maintainers resources, and optionally labeled ConfigMaps, whenever they change and every
+// resync, publishing the results as the Valid condition of the Maintainers and as Events
+type controller struct {
+	namespace string
+	resync    time.Duration
+	client    dynamic.Interface
+	recorder  record.EventRecorder
+	queue     workqueue.TypedRateLimitingInterface[controllerKey]
+	stores    sync.Map
+
+	mu sync.Mutex
+	// lastState is the outcome of the last validation of every object, an Event is only recorded when it changes
+	lastState map[types.UID]string
+}
+
+type controllerKey struct {
+	resource schema.GroupVersionResource
+	key      string
+}
+
+// maintainersSpec is the spec of a Maintainers resource: the teams of a maintainers file, and the index they are
+// validated against, a URL or the name of a ClusterRepo
+type maintainersSpec struct {
+	Teams       Maintainers       `json:"teams"`
+	Index       string            `json:"index"`
+	ClusterRepo string            `json:"clusterRepo"`
+	Aliases     map[string]string `json:"aliases"`
+}
+
+// maintainersStatus is the status of a Maintainers resource
+type maintainersStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	Errors             int                `json:"errors"`
+	Warnings           int                `json:"warnings"`
+	Findings           validate.Results   `json:"findings,omitempty"`
+}
+
+func (c *controller) watch(resource schema.GroupVersionResource, tweak dynamicinformer.TweakListOptionsFunc) cache.SharedIndexInformer {
+	informer := dynamicinformer.NewFilteredDynamicInformer(c.client, resource, c.namespace, c.resync, cache.Indexers{}, tweak).Informer()
+	c.stores.Store(resource, informer.GetStore())
+	enqueue := func(obj interface{}) {
+		if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+			c.queue.Add(controllerKey{resource: resource, key: key})
+		}
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				c.mu.Lock()
+				delete(c.lastState, u.GetUID())
+				c.mu.Unlock()
+			}
+		},
+	})
+	return informer
+}
+
+func (c *controller) processNext(ctx context.Context) bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+	if err := c.reconcile(ctx, key); err != nil {
+		slog.Error("failed to reconcile", "resource", key.resource.Resource, "key", key.key, "error", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// reconcile validates the object of the key, failing to load its maintainers file or index is a result published
+// like the others, only failing to publish it is retried
+func (c *controller) reconcile(ctx context.Context, key controllerKey) error {
+	store, _ := c.stores.Load(key.resource)
+	item, exists, err := store.(cache.Store).GetByKey(key.key)
+	if err != nil || !exists {
+		return err
+	}
+	obj := item.(*unstructured.Unstructured).DeepCopy()
+	spec, err := c.spec(key.resource, obj)
+	var results validate.Results
+	if err == nil {
+		results, err = c.validate(ctx, obj, spec)
+	}
+
+	condition := metav1.Condition{Type: conditionValid, Status: metav1.ConditionTrue, Reason: "Validated", Message: "the maintainers file is valid"}
+	errs := countErrors(results)
+	switch {
+	case err != nil:
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionUnknown, "LoadFailed", strings.TrimPrefix(err.Error(), "error: ")
+	case errs > 0:
+		condition.Status, condition.Reason = metav1.ConditionFalse, "Invalid"
+		condition.Message = fmt.Sprintf("%s and %s found", pluralize(errs, "error"), pluralize(len(results)-errs, "warning"))
+	case len(results) > 0:
+		condition.Message = fmt.Sprintf("the maintainers file is valid with %s", pluralize(len(results), "warning"))
+	}
+	c.recordEvent(obj, condition, results)
+	if key.resource != maintainersResource {
+		return nil
+	}
+	return c.updateStatus(ctx, obj, condition, results)
+}
+
+// spec returns the spec of a Maintainers resource, or that of a ConfigMap from its maintainers.yaml key and
+// annotations
+func (c *controller) spec(resource schema.GroupVersionResource, obj *unstructured.Unstructured) (maintainersSpec, error) {
+	var spec maintainersSpec
+	if resource == configMapResource {
+		data, _, _ := unstructured.NestedString(obj.Object, "data", "maintainers.yaml")
+		if err := yaml.Unmarshal([]byte(data), &spec.Teams); err != nil {
+			return spec, fmt.Errorf("failed to decode the maintainers.yaml key: %w", err)
+		}
+		spec.Index, spec.ClusterRepo = obj.GetAnnotations()[indexAnnotation], obj.GetAnnotations()[clusterRepoAnnotation]
+		return spec, nil
+	}
+	raw, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return spec, err
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return spec, fmt.Errorf("failed to decode the spec: %w", err)
+	}
+	return spec, nil
+}
+
+func (c *controller) validate(ctx context.Context, obj *unstructured.Unstructured, spec maintainersSpec) (validate.Results, error) {
+	indexURL, err := c.indexURL(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	index, err := decodeIndexFile(ctx, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	source := obj.GetNamespace() + "/" + obj.GetName()
+	return validateMaintainersResults(ctx, &Config{Aliases: spec.Aliases}, spec.Teams, index, source, indexURL)
+}
+
+// indexURL returns the URL of the index of the spec. Only remote indexes are loaded, the controller never reads its
+// own files for a resource
+func (c *controller) indexURL(ctx context.Context, spec maintainersSpec) (string, error) {
+	switch {
+	case spec.Index != "" && spec.ClusterRepo != "":
+		return "", errors.New("set either the index or the cluster repo, not both")
+	case spec.Index != "":
+		if !isRemote(spec.Index) && !isOCI(spec.Index) {
+			return "", fmt.Errorf("index [%s] is not an http(s) or oci:// URL", spec.Index)
+		}
+		return spec.Index, nil
+	case spec.ClusterRepo != "":
+		repo, err := c.client.Resource(clusterRepoResource).Get(ctx, spec.ClusterRepo, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get cluster repo [%s]: %w", spec.ClusterRepo, err)
+		}
+		url, _, _ := unstructured.NestedString(repo.Object, "spec", "url")
+		if url == "" {
+			return "", fmt.Errorf("cluster repo [%s] has no url, git cluster repos are not supported", spec.ClusterRepo)
+		}
+		return strings.TrimSuffix(url, "/") + "/index.yaml", nil
+	}
+	return "", errors.New("no index to validate against, set the index or the cluster repo")
+}
+
+// recordEvent records an Event when the outcome of the validation of obj changed since the last one
+func (c *controller) recordEvent(obj *unstructured.Unstructured, condition metav1.Condition, results validate.Results) {
+	state := condition.Reason + "\x00" + strings.Join(results.Strings(), "\n")
+	c.mu.Lock()
+	changed := c.lastState[obj.GetUID()] != state
+	c.lastState[obj.GetUID()] = state
+	c.mu.Unlock()
+	if !changed {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	if condition.Status != metav1.ConditionTrue {
+		eventType = corev1.EventTypeWarning
+	}
+	message := condition.Message
+	if len(results) > 0 {
+		message += ": " + results[0].Message
+	}
+	c.recorder.Event(obj, eventType, condition.Reason, message)
+}
+
+// updateStatus sets the status of a Maintainers resource, only writing it when it changed
+func (c *controller) updateStatus(ctx context.Context, obj *unstructured.Unstructured, condition metav1.Condition, results validate.Results) error {
+	var status maintainersStatus
+	if raw, ok, _ := unstructured.NestedMap(obj.Object, "status"); ok {
+		runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &status)
+	}
+	previous, _ := json.Marshal(status)
+	errs := countErrors(results)
+	status.ObservedGeneration = obj.GetGeneration()
+	condition.ObservedGeneration = obj.GetGeneration()
+	meta.SetStatusCondition(&status.Conditions, condition)
+	status.Errors, status.Warnings, status.Findings = errs, len(results)-errs, results
+	if current, _ := json.Marshal(status); string(current) == string(previous) {
+		return nil
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedMap(obj.Object, raw, "status"); err != nil {
+		return err
+	}
+	_, err = c.client.Resource(maintainersResource).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runDaemon(ctx context.Context, args []string) error {
+	var d daemon
+	var configFilePath, listen string
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	fs.StringVar(&d.maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&d.indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases and the notifications")
+	fs.DurationVar(&d.interval, "interval", 6*time.Hour, "how often the maintainers file is validated again")
+	fs.StringVar(&d.stateFilePath, "state-file", "", "file the findings are kept in across restarts, so that a restart notifies nothing that did not change")
+	fs.StringVar(&listen, "listen", "", "address the /healthz and /readyz probes are served on, e.g. :8080, disabled if empty")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if d.interval <= 0 {
+		return fmt.Errorf("error: invalid --interval [%s], it must be positive", d.interval)
+	}
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	if len(config.Notifications) == 0 && len(config.Routes) == 0 {
+		slog.Warn("no notifications in the config file, changes of the findings are only logged", "config", configFilePath)
+	}
+	d.config = config
+	if err := d.loadState(); err != nil {
+		return err
+	}
+	if listen != "" {
+		mux := http.NewServeMux()
+		d.health.register(mux)
+		go func() {
+			if err := listenAndServe(ctx, listen, mux); err != nil {
+				slog.Error("probe server stopped", "address", listen, "error", err)
+			}
+		}()
+	}
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// daemon validates the maintainers file against the index every interval, notifying the findings that appear or
+// are resolved since the previous validation
+type daemon struct {
+	maintainersFilePath string
+	indexFilePath       string
+	stateFilePath       string
+	interval            time.Duration
+	config              *Config
+	// findings are those of the last validation, nil before the first one without a state file
+	findings validate.Results
+	// maintainers are those of the last validation, the routes match the teams of the findings with them
+	maintainers Maintainers
+	// digests are what every digest route was last sent, kept in the state file with the findings
+	digests map[string]*digestState
+	// health is ready from the first validation that loaded the files on
+	health health
+}
+
+// check validates once, a validation that fails to load the files changes nothing and is retried on the next tick
+func (d *daemon) check(ctx context.Context) {
+	findings, err := d.validate(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			slog.Error("failed to validate", "maintainersFile", d.maintainersFilePath, "index", d.indexFilePath, "error", err)
+		}
+		return
+	}
+	d.health.ready.Store(true)
+	change := findingsChange{Source: d.maintainersFilePath, New: subtractResults(findings, d.findings), Resolved: subtractResults(d.findings, findings)}
+	d.findings = findings
+	slog.Info("validated", "maintainersFile", d.maintainersFilePath, "findings", len(findings), "new", len(change.New), "resolved", len(change.Resolved))
+	changed := len(change.New) > 0 || len(change.Resolved) > 0
+	if changed {
+		for _, n := range d.config.Notifications {
+			if err := n.send(ctx, &d.config.Templates, change); err != nil {
+				slog.Error("failed to notify", "type", n.Type, "error", err)
+			}
+		}
+		routeChange(ctx, d.config, d.maintainers, change)
+	}
+	if sendDigests(ctx, d.config, d.maintainers, d.maintainersFilePath, findings, d.digests, time.Now()) {
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	if err := d.saveState(); err != nil {
+		slog.Error("failed to save the findings", "stateFile", d.stateFilePath, "error", err)
+	}
+}
+
+func (d *daemon) validate(ctx context.Context) (validate.Results, error) {
+	maintainers, err := decodeMaintainersFile(ctx, d.maintainersFilePath)
+	if err != nil {
+		return nil, err
+	}
+	index, err := decodeIndexFile(ctx, d.indexFilePath)
+	if err != nil {
+		return nil, err
+	}
+	d.maintainers = maintainers
+	findings, err := validateMaintainersResults(ctx, d.config, maintainers, index, d.maintainersFilePath, d.indexFilePath)
+	if err != nil {
+		return nil, err
+	}
+	// The critical charts are paged on every validation, PagerDuty deduplicates the events of a gap that lasts
+	if d.config.PagerDuty.RoutingKey != "" {
+		if err := pageCriticalCharts(ctx, d.config, maintainers, index, d.maintainersFilePath); err != nil {
+			slog.Error("failed to page the critical charts", "error", err)
+		}
+	}
+	if findings == nil {
+		findings = validate.Results{}
+	}
+	return findings, nil
+}
+
+// daemonState is the content of the state file. Older state files only hold the findings, as an array
+type daemonState struct {
+	Findings validate.Results        `json:"findings"`
+	Digests  map[string]*digestState `json:"digests,omitempty"`
+}
+
+func (d *daemon) loadState() error {
+	d.digests = make(map[string]*digestState)
+	if d.stateFilePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(d.stateFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &d.findings)
+	} else {
+		state := daemonState{Digests: d.digests}
+		err = json.Unmarshal(data, &state)
+		d.findings = state.Findings
+	}
+	if err != nil {
+		return fmt.Errorf("error: failed to decode state file [%s]: %w", d.stateFilePath, err)
+	}
+	return nil
+}
+
+func (d *daemon) saveState() error {
+	if d.stateFilePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(daemonState{Findings: d.findings, Digests: d.digests}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(d.stateFilePath, append(data, '\n'), 0o644)
+}
+
+// subtractResults returns the results of a that are not in b, in their order
+func subtractResults(a, b validate.Results) validate.Results {
+	seen := make(map[validate.Result]bool, len(b))
+	for _, r := range b {
+		seen[r] = true
+	}
+	var diff validate.Results
+	for _, r := range a {
+		if !seen[r] {
+			diff = append(diff, r)
+		}
+	}
+	return diff
+}
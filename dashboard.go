@@ -0,0 +1,124 @@
+package main
+
+import (
+	_ "embed"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+//go:embed ui/dashboard.html
+var dashboardTemplate string
+
+var dashboardPage = template.Must(template.New("dashboard").Funcs(template.FuncMap{"join": strings.Join}).Parse(dashboardTemplate))
+
+// dashboard is the data the dashboard is rendered with, what the filters do not match is left out of the tables
+type dashboard struct {
+	MaintainersFile string
+	IndexFile       string
+	LoadedAt        time.Time
+	// Query, Team and Severity are the filters: a search of the chart and team names and the messages, the exact
+	// name of a team and the severity of the findings
+	Query    string
+	Team     string
+	Severity string
+
+	TeamNames []string
+	Teams     []teamSummary
+	Charts    []ownershipPageRow
+	// Validated is false without an index, the fields below are then empty
+	Validated   bool
+	ValidatedAt time.Time
+	IndexCharts int
+	Coverage    float64
+	Errors      int
+	Warnings    int
+	Findings    validate.Results
+	Unowned     []string
+	// ChartCount is the number of charts of the maintainers file, before filtering
+	ChartCount int
+}
+
+// dashboard renders the teams, charts, coverage and findings of the maintainers file as a page, filtered by the q,
+// team and severity parameters
+func (s *apiServer) dashboard(w http.ResponseWriter, r *http.Request) {
+	maintainers, loadedAt := s.snapshot()
+	validation := s.lastValidation()
+
+	d := dashboard{
+		MaintainersFile: s.maintainersFilePath,
+		IndexFile:       s.indexFilePath,
+		LoadedAt:        loadedAt,
+		Query:           strings.TrimSpace(r.URL.Query().Get("q")),
+		Team:            r.URL.Query().Get("team"),
+		Severity:        r.URL.Query().Get("severity"),
+	}
+	query := strings.ToLower(d.Query)
+	matches := func(values ...string) bool {
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), query) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, m := range maintainers {
+		d.TeamNames = append(d.TeamNames, m.Name)
+		d.ChartCount += len(m.Charts)
+		if d.Team != "" && m.Name != d.Team {
+			continue
+		}
+		teamMatches := matches(m.Name)
+		var charts int
+		for _, chart := range m.Charts {
+			if teamMatches || matches(chart.Name) {
+				d.Charts = append(d.Charts, ownershipPageRow{Chart: chart, Team: m})
+				charts++
+			}
+		}
+		if teamMatches || charts > 0 {
+			d.Teams = append(d.Teams, teamSummary{Name: m.Name, Email: m.Contact.Email, SlackChannel: m.Contact.SlackChannel, URL: m.Contact.URL, Charts: len(m.Charts)})
+		}
+	}
+	sort.SliceStable(d.Charts, func(i, j int) bool { return d.Charts[i].Chart.Name < d.Charts[j].Chart.Name })
+	if validation != nil {
+		d.Validated = true
+		d.ValidatedAt = validation.validatedAt
+		d.IndexCharts = validation.indexCharts
+		if d.IndexCharts > 0 {
+			d.Coverage = 100 * float64(d.IndexCharts-len(validation.unowned)) / float64(d.IndexCharts)
+		}
+		for _, f := range validation.findings {
+			if f.Severity == validate.SeverityError {
+				d.Errors++
+			} else {
+				d.Warnings++
+			}
+			if d.Severity != "" && string(f.Severity) != d.Severity {
+				continue
+			}
+			if d.Team != "" {
+				if m, _ := maintainers.FindChart(f.Chart); m == nil || m.Name != d.Team {
+					continue
+				}
+			}
+			if matches(f.Chart, f.Message) {
+				d.Findings = append(d.Findings, f)
+			}
+		}
+		for _, name := range validation.unowned {
+			if d.Team == "" && matches(name) {
+				d.Unowned = append(d.Unowned, name)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardPage.Execute(w, d); err != nil {
+		slog.Error("failed to render the dashboard", "error", err)
+	}
+}
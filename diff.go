@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const diffContext = 3
+
+// unifiedDiff renders the line changes from a to b in unified diff format, or "" if they are equal
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	aLines, bLines := splitLines(string(a)), splitLines(string(b))
+	ops := diffLines(aLines, bLines)
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and grow the hunk while at most twice the context separates changes, like diff -u
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				if i-last-1 > 2*diffContext {
+					break
+				}
+				last = i
+			}
+		}
+		from, to := max(first-diffContext, start), min(last+diffContext+1, len(ops))
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+		}
+		aStart, bStart := ops[from].aLine, ops[from].bLine
+		aCount, bCount := 0, 0
+		var body strings.Builder
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+			fmt.Fprintf(&body, "%c%s\n", op.kind, op.text)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n%s", hunkRange(aStart, aCount), hunkRange(bStart, bCount), body.String())
+		start = to
+	}
+	return out.String()
+}
+
+type diffOp struct {
+	kind  byte
+	text  string
+	aLine int
+	bLine int
+}
+
+// diffLines returns the edit script turning a into b from their longest common subsequence, aLine and bLine are
+// the 0-based positions in a and b the op applies at
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		default:
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		}
+	}
+	return ops
+}
+
+// diffName returns path the way it is written after a/ and b/ in diff headers
+func diffName(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "/")
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
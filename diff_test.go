@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	b := "a\nB\nc\nd\ne\nf\ng\nh\nI\nj\nk\n"
+	want := `--- a/maintainers.yaml
++++ b/maintainers.yaml
+@@ -1,10 +1,11 @@
+ a
+-b
++B
+ c
+ d
+ e
+ f
+ g
+ h
+-i
++I
+ j
++k
+`
+	if got := unifiedDiff("a/maintainers.yaml", "b/maintainers.yaml", []byte(a), []byte(b)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if got := unifiedDiff("a", "b", []byte(a), []byte(a)); got != "" {
+		t.Errorf("diff of equal files is not empty:\n%s", got)
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 30; i++ {
+		line := strings.Repeat("x", i+1)
+		a = append(a, line)
+		if i == 2 || i == 25 {
+			line = "changed"
+		}
+		b = append(b, line)
+	}
+	got := unifiedDiff("a", "b", []byte(strings.Join(a, "\n")+"\n"), []byte(strings.Join(b, "\n")+"\n"))
+	// Changes more than twice the context apart are separate hunks
+	if hunks := strings.Count(got, "\n@@ "); hunks != 2 {
+		t.Errorf("got %d hunks, want 2:\n%s", hunks, got)
+	}
+	if !strings.Contains(got, "@@ -1,6 +1,6 @@\n") || !strings.Contains(got, "@@ -23,7 +23,7 @@\n") {
+		t.Errorf("unexpected hunk ranges:\n%s", got)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := [][2]string{
+		{"", ""},
+		{"", "a\nb"},
+		{"a\nb", ""},
+		{"a\nb\nc", "a\nc"},
+		{"a\nb\nc", "c\nb\na"},
+		{"- name: a\n\n- name: b", "- name: b\n\n- name: a\n\n- name: c"},
+	}
+	for _, tt := range tests {
+		a, b := splitLines(tt[0]), splitLines(tt[1])
+		var gotA, gotB []string
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				gotA = append(gotA, op.text)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.text)
+			}
+		}
+		// Dropping the added lines gives back a, dropping the removed lines gives b
+		if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+			t.Errorf("diff of %q and %q does not give both back: %q and %q", tt[0], tt[1], gotA, gotB)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailSettings is the SMTP server the digests of --email-teams are sent through. Like the notification URLs, the
+// password can name environment variables
+type EmailSettings struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	// TLS connects with implicit TLS, usually on port 465, instead of upgrading the connection with STARTTLS when the
+	// server offers it
+	TLS bool `yaml:"tls"`
+}
+
+const defaultSMTPPort = 587
+
+var emailHTML = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif">
+{{- range .Sections }}
+<p>{{ .Title }}:</p>
+<ul>
+{{- range .Items }}
+<li>{{ if .URL }}<a href="{{ .URL }}">{{ .Text }}</a>{{ else }}{{ .Text }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}
+<p style="color: #777; font-size: 0.9em">Sent by cowhand{{ with .Team }} to the contact of {{ . }} in the maintainers file{{ end }}.</p>
+</body>
+</html>
+`))
+
+// emailTeams sends every message as a digest to the email of its team, a team without an email, or whose email
+// cannot be sent, is logged and does not stop the others
+func emailTeams(ctx context.Context, config *Config, messages []teamMessage) error {
+	if config.Email.Host == "" {
+		return errors.New("error: no email host in the config file")
+	}
+	var failed int
+	for _, message := range messages {
+		if message.team.Contact.Email == "" {
+			slog.Warn("team has no email, not notified", "team", message.team.Name)
+			continue
+		}
+		to, err := emailAddresses(message.team.Contact.Email)
+		if err == nil {
+			err = sendEmail(ctx, config, to, message.templateData(), []cardSection{{title: message.title, items: message.items}})
+		}
+		if err != nil {
+			slog.Error("failed to email team", "team", message.team.Name, "email", message.team.Contact.Email, "error", err)
+			failed++
+			continue
+		}
+		slog.Info("emailed team", "team", message.team.Name, "email", message.team.Contact.Email)
+	}
+	if failed > 0 {
+		return fmt.Errorf("error: failed to email %s", pluralize(failed, "team"))
+	}
+	return nil
+}
+
+// emailAddresses parses the comma separated addresses of a contact
+func emailAddresses(s string) ([]string, error) {
+	list, err := mail.ParseAddressList(s)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, len(list))
+	for i, address := range list {
+		addresses[i] = address.Address
+	}
+	return addresses, nil
+}
+
+// sendEmail renders the sections as an email and sends it through the SMTP server of the config
+func sendEmail(ctx context.Context, config *Config, to []string, data notificationData, sections []cardSection) error {
+	if config.Email.Host == "" {
+		return errors.New("error: no email host in the config file")
+	}
+	email, err := renderEmail(&config.Templates, config.Email.From, to, time.Now(), data, sections)
+	if err != nil {
+		return err
+	}
+	return config.Email.send(ctx, to, email)
+}
+
+// renderEmail renders the sections as a multipart/alternative email, the plain text part is the fallback of
+// clients that do not render the HTML one. Each of the subject and the parts can be templated
+func renderEmail(templates *NotificationTemplates, from string, to []string, now time.Time, data notificationData, sections []cardSection) ([]byte, error) {
+	subject, templated, err := templates.execute(templateEmailSubject, data)
+	if err != nil {
+		return nil, err
+	}
+	if !templated {
+		subject = "cowhand: " + data.Title
+	}
+	plain, templated, err := templates.execute(templateEmailText, data)
+	if err != nil {
+		return nil, err
+	}
+	if !templated {
+		var b strings.Builder
+		for _, section := range sections {
+			fmt.Fprintf(&b, "%s:\n\n", section.title)
+			for _, item := range section.items {
+				if item.url != "" {
+					fmt.Fprintf(&b, "- %s: %s\n", item.text, item.url)
+				} else {
+					fmt.Fprintf(&b, "- %s\n", item.text)
+				}
+			}
+			b.WriteString("\n")
+		}
+		plain = strings.TrimSuffix(b.String(), "\n")
+	}
+	html, templated, err := templates.executeHTML(data)
+	if err != nil {
+		return nil, err
+	}
+	if !templated {
+		type item struct{ Text, URL string }
+		type section struct {
+			Title string
+			Items []item
+		}
+		page := struct {
+			Sections []section
+			Team     string
+		}{}
+		if data.Team != nil {
+			page.Team = data.Team.Name
+		}
+		for _, s := range sections {
+			rendered := section{Title: s.title}
+			for _, i := range s.items {
+				rendered.Items = append(rendered.Items, item{i.text, i.url})
+			}
+			page.Sections = append(page.Sections, rendered)
+		}
+		var b strings.Builder
+		if err := emailHTML.Execute(&b, page); err != nil {
+			return nil, err
+		}
+		html = b.String()
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", plain},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}, "Content-Transfer-Encoding": {"quoted-printable"}})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	id := make([]byte, 16)
+	rand.Read(id)
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	domain := sender.Address[strings.LastIndex(sender.Address, "@")+1:]
+	for _, header := range [][2]string{
+		{"From", from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject), " "))},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", "<" + hex.EncodeToString(id) + "@" + domain + ">"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	} {
+		fmt.Fprintf(&b, "%s: %s\r\n", header[0], header[1])
+	}
+	b.WriteString("\r\n")
+	b.Write(body.Bytes())
+	return b.Bytes(), nil
+}
+
+// send delivers the email to the SMTP server, authenticating when a username is set
+func (s EmailSettings) send(ctx context.Context, to []string, data []byte) error {
+	port := s.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	conn, err := (&net.Dialer{Timeout: 30 * time.Second}).DialContext(ctx, "tcp", net.JoinHostPort(s.Host, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	// net/smtp has no context, the deadline bounds the whole conversation instead
+	conn.SetDeadline(time.Now().Add(time.Minute))
+	tlsConfig := &tls.Config{ServerName: s.Host}
+	if s.TLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok && !s.TLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		password, err := resolveSecret(ctx, s.Password)
+		if err != nil {
+			return err
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.Username, password, s.Host)); err != nil {
+			return err
+		}
+	}
+	sender, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+	if err := c.Mail(sender.Address); err != nil {
+		return err
+	}
+	for _, address := range to {
+		if err := c.Rcpt(address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
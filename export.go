@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func runExport(ctx context.Context, args []string) error {
+	if len(args) > 0 && args[0] == "sqlite" {
+		return runExportSQLite(ctx, args[1:])
+	}
+	if len(args) == 0 || args[0] != "csv" {
+		return errors.New("error: usage: cowhand export csv [-o <file>] | cowhand export sqlite <file>")
+	}
+	var (
+		maintainersFilePath string
+		output              string
+	)
+	fs := flag.NewFlagSet("export csv", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&output, "o", "-", "path the csv is written to, - for stdout")
+	fs.Parse(args[1:])
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		return writeOwnershipCSV(os.Stdout, maintainers)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := writeOwnershipCSV(file, maintainers); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeOwnershipCSV writes one row per chart with its team and the team contact, in the columns import csv reads
+// back plus maintainedVersions. Labels are separated by semicolons so cells need no quoting
+func writeOwnershipCSV(w io.Writer, maintainers Maintainers) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"chart", "team", "email", "slack", "url", "labels", "generateIssue", "maintainedVersions"})
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			cw.Write([]string{
+				chart.Name,
+				m.Name,
+				m.Contact.Email,
+				m.Contact.SlackChannel,
+				m.Contact.URL,
+				strings.Join(chart.GithubLabels, ";"),
+				strconv.FormatBool(chart.GenerateIssue),
+				chart.MaintainedVersions,
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
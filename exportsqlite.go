@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+	// Registers the sqlite driver, a pure Go one so that cowhand still builds without cgo
+	_ "modernc.org/sqlite"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// ownershipSchema are the tables export sqlite writes. A chart listed by several teams, each maintaining a range of
+// its versions, has a row per team, and the index tables join the charts by name
+const ownershipSchema = `
+CREATE TABLE teams (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+CREATE TABLE contacts (
+	team_id INTEGER NOT NULL REFERENCES teams(id),
+	kind TEXT NOT NULL CHECK (kind IN ('email', 'slack', 'url')),
+	value TEXT NOT NULL
+);
+CREATE TABLE charts (
+	id INTEGER PRIMARY KEY,
+	team_id INTEGER NOT NULL REFERENCES teams(id),
+	name TEXT NOT NULL,
+	generate_issue INTEGER NOT NULL,
+	maintained_versions TEXT NOT NULL
+);
+CREATE INDEX charts_name ON charts(name);
+CREATE TABLE labels (
+	chart_id INTEGER NOT NULL REFERENCES charts(id),
+	label TEXT NOT NULL
+);
+CREATE TABLE findings (
+	rule TEXT NOT NULL,
+	severity TEXT NOT NULL,
+	chart TEXT NOT NULL,
+	message TEXT NOT NULL
+);
+CREATE TABLE index_versions (
+	chart TEXT NOT NULL,
+	version TEXT NOT NULL,
+	app_version TEXT NOT NULL,
+	created TEXT NOT NULL,
+	deprecated INTEGER NOT NULL,
+	digest TEXT NOT NULL
+);
+CREATE INDEX index_versions_chart ON index_versions(chart);
+`
+
+func runExportSQLite(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		configFilePath      string
+		output              string
+	)
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		output, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("export sqlite", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file, empty to leave out the findings and the index versions")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if output == "" {
+		output = fs.Arg(0)
+	}
+	if output == "" {
+		return errors.New("error: usage: cowhand export sqlite <file>")
+	}
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	var (
+		index    *repo.IndexFile
+		findings validate.Results
+	)
+	if indexFilePath != "" {
+		if index, err = decodeIndexFile(ctx, indexFilePath); err != nil {
+			return err
+		}
+		if findings, err = validateMaintainersResults(ctx, config, maintainers, index, maintainersFilePath, indexFilePath); err != nil {
+			return err
+		}
+	}
+	// The database is written next to the output and renamed over it, so a failed export leaves the previous one
+	tmp := filepath.Join(filepath.Dir(output), "."+filepath.Base(output)+".tmp")
+	os.Remove(tmp)
+	if err := writeOwnershipSQLite(ctx, tmp, maintainers, index, findings); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	fmt.Printf("exported %s and %s to [%s]\n", pluralize(len(maintainers), "team"), pluralize(len(findings), "finding"), output)
+	return nil
+}
+
+// writeOwnershipSQLite creates the database at path with the tables of ownershipSchema, the index ones are empty
+// when index is nil
+func writeOwnershipSQLite(ctx context.Context, path string, maintainers Maintainers, index *repo.IndexFile, findings validate.Results) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, ownershipSchema); err != nil {
+		return err
+	}
+	var chartID int64
+	for i, m := range maintainers {
+		teamID := int64(i + 1)
+		if _, err := tx.ExecContext(ctx, "INSERT INTO teams (id, name) VALUES (?, ?)", teamID, m.Name); err != nil {
+			return fmt.Errorf("error: failed to export team [%s]: %w", m.Name, err)
+		}
+		for _, contact := range contactRows(m.Contact) {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO contacts (team_id, kind, value) VALUES (?, ?, ?)", teamID, contact[0], contact[1]); err != nil {
+				return err
+			}
+		}
+		for _, chart := range m.Charts {
+			chartID++
+			if _, err := tx.ExecContext(ctx, "INSERT INTO charts (id, team_id, name, generate_issue, maintained_versions) VALUES (?, ?, ?, ?, ?)", chartID, teamID, chart.Name, chart.GenerateIssue, chart.MaintainedVersions); err != nil {
+				return err
+			}
+			for _, label := range chart.GithubLabels {
+				if _, err := tx.ExecContext(ctx, "INSERT INTO labels (chart_id, label) VALUES (?, ?)", chartID, label); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, f := range findings {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO findings (rule, severity, chart, message) VALUES (?, ?, ?, ?)", f.Rule, string(f.Severity), f.Chart, f.Message); err != nil {
+			return err
+		}
+	}
+	if index != nil {
+		for _, name := range validate.IndexChartNames(index) {
+			for _, v := range index.Entries[name] {
+				created := ""
+				if !v.Created.IsZero() {
+					created = v.Created.UTC().Format("2006-01-02T15:04:05Z")
+				}
+				if _, err := tx.ExecContext(ctx, "INSERT INTO index_versions (chart, version, app_version, created, deprecated, digest) VALUES (?, ?, ?, ?, ?, ?)", name, v.Version, v.AppVersion, created, v.Deprecated, v.Digest); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// contactRows returns the kind and the value of every way to reach the team, an email field holding several
+// addresses is a row per address
+func contactRows(c Contact) [][2]string {
+	var rows [][2]string
+	if c.Email != "" {
+		if list, err := mail.ParseAddressList(c.Email); err == nil {
+			for _, address := range list {
+				rows = append(rows, [2]string{"email", address.Address})
+			}
+		} else {
+			rows = append(rows, [2]string{"email", c.Email})
+		}
+	}
+	if c.SlackChannel != "" {
+		rows = append(rows, [2]string{"slack", c.SlackChannel})
+	}
+	if c.URL != "" {
+		rows = append(rows, [2]string{"url", c.URL})
+	}
+	return rows
+}
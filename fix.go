@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// fixMaintainersNode repairs the problems of the maintainers file that have only one possible fix and returns what
+// it changed: whitespace around values, duplicate labels, generateIssue enabled on crd charts and charts listed
+// twice with the same fields by the same team. Charts listed by different teams are left for a human to resolve
+func fixMaintainersNode(doc *yaml.Node) ([]string, error) {
+	var fixes []string
+	for _, team := range teamNodes(doc) {
+		fixes = append(fixes, trimScalarNodes(team, nodeName(team))...)
+		teamName := nodeName(team)
+		charts := mappingValue(team, "charts")
+		if charts == nil || charts.Kind != yaml.SequenceNode {
+			continue
+		}
+		var kept []*yaml.Node
+		var keptCharts []Chart
+		for _, node := range charts.Content {
+			chartName := nodeName(node)
+			if labels := mappingValue(node, "githubLabels"); labels != nil && labels.Kind == yaml.SequenceNode {
+				seen := make(map[string]struct{})
+				unique := labels.Content[:0]
+				for _, label := range labels.Content {
+					if _, ok := seen[label.Value]; ok {
+						fixes = append(fixes, fmt.Sprintf("removed duplicate label [%s] from chart [%s]", label.Value, chartName))
+						continue
+					}
+					seen[label.Value] = struct{}{}
+					unique = append(unique, label)
+				}
+				labels.Content = unique
+			}
+			if generateIssue := mappingValue(node, "generateIssue"); strings.HasSuffix(chartName, "-crd") && generateIssue != nil && generateIssue.Value == "true" {
+				generateIssue.Value = "false"
+				fixes = append(fixes, fmt.Sprintf("set [generateIssue: false] on crd chart [%s]", chartName))
+			}
+			var chart Chart
+			if err := node.Decode(&chart); err != nil {
+				return nil, err
+			}
+			duplicate := false
+			for _, other := range keptCharts {
+				duplicate = duplicate || reflect.DeepEqual(normalizeChart(other), normalizeChart(chart))
+			}
+			if duplicate {
+				fixes = append(fixes, fmt.Sprintf("removed duplicate entry of chart [%s] from [%s]", chartName, teamName))
+				continue
+			}
+			kept = append(kept, node)
+			keptCharts = append(keptCharts, chart)
+		}
+		charts.Content = kept
+	}
+	return fixes, nil
+}
+
+// trimScalarNodes trims the whitespace around every value under node, keys are left as they are
+func trimScalarNodes(node *yaml.Node, team string) []string {
+	var fixes []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		trimmed := strings.TrimSpace(node.Value)
+		switch {
+		case trimmed == node.Value:
+		case trimmed == strings.TrimSpace(team):
+			fixes = append(fixes, fmt.Sprintf("trimmed the whitespace around the name of team [%s]", trimmed))
+		default:
+			fixes = append(fixes, fmt.Sprintf("trimmed the whitespace around [%s] in [%s]", trimmed, strings.TrimSpace(team)))
+		}
+		node.Value = trimmed
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			fixes = append(fixes, trimScalarNodes(node.Content[i], team)...)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			fixes = append(fixes, trimScalarNodes(item, team)...)
+		}
+	}
+	return fixes
+}
+
+// normalizeChart returns the chart with empty lists set to nil, githubLabels: [] and no labels are the same entry
+func normalizeChart(c Chart) Chart {
+	if len(c.GithubLabels) == 0 {
+		c.GithubLabels = nil
+	}
+	if len(c.Repositories) == 0 {
+		c.Repositories = nil
+	}
+	if len(c.AcknowledgedDependencies) == 0 {
+		c.AcknowledgedDependencies = nil
+	}
+	return c
+}
+
+// fixMaintainersFile applies fixMaintainersNode to the maintainers file and prints what it changed, opening a pull
+// request with the changes instead of writing them if pr asks to
+func fixMaintainersFile(ctx context.Context, maintainersFilePath string, pr *pullRequestFlags) error {
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	fixes, err := fixMaintainersNode(doc)
+	if err != nil {
+		return err
+	}
+	if len(fixes) == 0 {
+		return nil
+	}
+	fixed, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, fixed))
+	if pr.create {
+		fmt.Println()
+		if err := pr.open(ctx, "validate --fix", maintainersFilePath, fixed, fmt.Sprintf("Fix %s in the maintainers file", pluralize(len(fixes), "problem")), fixes); err != nil {
+			return err
+		}
+		fmt.Println()
+		return nil
+	}
+	if err := os.WriteFile(maintainersFilePath, fixed, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\nfixed %s in [%s]:\n", pluralize(len(fixes), "problem"), maintainersFilePath)
+	for _, fix := range fixes {
+		fmt.Printf("- %s\n", fix)
+	}
+	fmt.Println()
+	return nil
+}
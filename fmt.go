@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func runFmt(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		check               bool
+	)
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.BoolVar(&check, "check", false, "only report whether the file is formatted and print the diff, failing if it is not")
+	fs.Parse(args)
+
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	formatMaintainersNode(doc)
+	formatted, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	diff := unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, formatted)
+	if diff == "" {
+		return nil
+	}
+	if check {
+		fmt.Print(diff)
+		return fmt.Errorf("error: maintainers file [%s] is not formatted, run cowhand fmt", maintainersFilePath)
+	}
+	if err := os.WriteFile(maintainersFilePath, formatted, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("formatted [%s]\n", maintainersFilePath)
+	return nil
+}
+
+// formatMaintainersNode sorts the teams and their charts by name and trims, deduplicates and sorts the labels of
+// every chart. Nodes are moved rather than rewritten so the comments attached to them move along
+func formatMaintainersNode(doc *yaml.Node) {
+	teams := teamNodes(doc)
+	sort.SliceStable(teams, func(i, j int) bool {
+		return strings.ToLower(nodeName(teams[i])) < strings.ToLower(nodeName(teams[j]))
+	})
+	for _, team := range teams {
+		charts := mappingValue(team, "charts")
+		if charts == nil || charts.Kind != yaml.SequenceNode {
+			continue
+		}
+		formatSequenceStyle(charts)
+		sort.SliceStable(charts.Content, func(i, j int) bool {
+			return nodeName(charts.Content[i]) < nodeName(charts.Content[j])
+		})
+		for _, chart := range charts.Content {
+			if labels := mappingValue(chart, "githubLabels"); labels != nil && labels.Kind == yaml.SequenceNode {
+				formatLabels(labels)
+			}
+		}
+	}
+}
+
+func formatLabels(labels *yaml.Node) {
+	seen := make(map[string]struct{})
+	kept := labels.Content[:0]
+	for _, label := range labels.Content {
+		label.Value = strings.TrimSpace(label.Value)
+		if _, ok := seen[label.Value]; ok || label.Value == "" {
+			continue
+		}
+		seen[label.Value] = struct{}{}
+		kept = append(kept, label)
+	}
+	labels.Content = kept
+	sort.SliceStable(labels.Content, func(i, j int) bool { return labels.Content[i].Value < labels.Content[j].Value })
+	formatSequenceStyle(labels)
+}
+
+// formatSequenceStyle writes empty lists as [] and others one item per line
+func formatSequenceStyle(seq *yaml.Node) {
+	if len(seq.Content) == 0 {
+		seq.Style = yaml.FlowStyle
+		return
+	}
+	seq.Style = 0
+}
+
+// nodeName returns the name field of a mapping node, or "" if it has none
+func nodeName(mapping *yaml.Node) string {
+	if name := mappingValue(mapping, "name"); name != nil {
+		return name.Value
+	}
+	return ""
+}
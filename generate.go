@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ownershipPage is the data the docs templates are executed with
+type ownershipPage struct {
+	// Teams are in the order of the maintainers file
+	Teams Maintainers
+	// Charts has a row per chart sorted by name
+	Charts []ownershipPageRow
+}
+
+type ownershipPageRow struct {
+	Chart Chart
+	Team  *Maintainer
+}
+
+var docsTemplates = map[string]string{
+	"markdown": `# Chart owners
+
+| Chart | Team | Slack | Issue labels |
+| --- | --- | --- | --- |
+{{- range .Charts }}
+| {{ .Chart.Name }}{{ with .Chart.MaintainedVersions }} ({{ . }}){{ end }} | {{ .Team.Name }} | {{ .Team.Contact.SlackChannel }} | {{ join .Chart.GithubLabels ", " }} |
+{{- end }}
+`,
+	"asciidoc": `= Chart owners
+
+|===
+| Chart | Team | Slack | Issue labels
+{{ range .Charts }}
+| {{ .Chart.Name }}{{ with .Chart.MaintainedVersions }} ({{ . }}){{ end }}
+| {{ .Team.Name }}
+| {{ .Team.Contact.SlackChannel }}
+| {{ join .Chart.GithubLabels ", " }}
+{{ end -}}
+|===
+`,
+}
+
+func runGenerate(ctx context.Context, args []string) error {
+	if len(args) > 0 && args[0] == "docs" {
+		return runGenerateDocs(ctx, args[1:])
+	}
+	if len(args) > 0 && args[0] == "backstage" {
+		return runGenerateBackstage(ctx, args[1:])
+	}
+	if len(args) > 0 && args[0] == "owners" {
+		return runGenerateOwners(ctx, args[1:])
+	}
+	return errors.New("error: usage: cowhand generate docs|backstage|owners")
+}
+
+func runGenerateDocs(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		templatePath        string
+		format              string
+		output              string
+		check               bool
+	)
+	fs := flag.NewFlagSet("generate docs", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&templatePath, "template", "", "text/template file rendered with the .Teams and .Charts of the maintainers file, overrides --format")
+	fs.StringVar(&format, "format", "markdown", "built-in template to render, markdown or asciidoc")
+	fs.StringVar(&output, "o", "-", "path the page is written to, - for stdout")
+	fs.BoolVar(&check, "check", false, "only report whether the page at -o is up to date and print the diff, failing if it is not")
+	fs.Parse(args)
+	if check && output == "-" {
+		return errors.New("error: --check needs the page to compare against with -o")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	text, ok := docsTemplates[format]
+	name := format
+	if templatePath != "" {
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return err
+		}
+		text, ok, name = string(data), true, templatePath
+	}
+	if !ok {
+		return fmt.Errorf("error: unknown format [%s], use markdown or asciidoc", format)
+	}
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("error: failed to parse template [%s]: %w", name, err)
+	}
+	var page bytes.Buffer
+	if err := tmpl.Execute(&page, newOwnershipPage(maintainers)); err != nil {
+		return fmt.Errorf("error: failed to render template [%s]: %w", name, err)
+	}
+
+	if output == "-" {
+		_, err := os.Stdout.Write(page.Bytes())
+		return err
+	}
+	if check {
+		current, err := os.ReadFile(output)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if diff := unifiedDiff("a/"+diffName(output), "b/"+diffName(output), current, page.Bytes()); diff != "" {
+			fmt.Print(diff)
+			return fmt.Errorf("error: [%s] is out of date, run cowhand generate docs", output)
+		}
+		return nil
+	}
+	if err := os.WriteFile(output, page.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote [%s]\n", output)
+	return nil
+}
+
+func newOwnershipPage(maintainers Maintainers) ownershipPage {
+	page := ownershipPage{Teams: maintainers}
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			page.Charts = append(page.Charts, ownershipPageRow{Chart: chart, Team: m})
+		}
+	}
+	sort.SliceStable(page.Charts, func(i, j int) bool { return page.Charts[i].Chart.Name < page.Charts[j].Chart.Name })
+	return page
+}
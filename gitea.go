@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type giteaClient struct {
+	*restClient
+}
+
+type giteaLabel struct {
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type giteaIssue struct {
+	Number  int          `json:"number"`
+	Title   string       `json:"title"`
+	HTMLURL string       `json:"html_url"`
+	Labels  []giteaLabel `json:"labels"`
+}
+
+func newGiteaClient(baseURL, token string, transport http.RoundTripper) *giteaClient {
+	return &giteaClient{newRESTClient("gitea", baseURL, bearerHeader("token", token), transport)}
+}
+
+func (i giteaIssue) remote() remoteIssue {
+	issue := remoteIssue{Number: i.Number, Title: i.Title, URL: i.HTMLURL}
+	for _, label := range i.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue
+}
+
+func (c *giteaClient) labels(ctx context.Context, repo string) ([]giteaLabel, error) {
+	var labels []giteaLabel
+	err := c.getPaginated(ctx, fmt.Sprintf("/repos/%s/labels?limit=50", repo), func(data []byte) error {
+		var page []giteaLabel
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		labels = append(labels, page...)
+		return nil
+	})
+	return labels, err
+}
+
+// labelIDs resolves label names to IDs, which is how Gitea references labels on issues and pull requests
+func (c *giteaClient) labelIDs(ctx context.Context, repo string, names []string) ([]int64, error) {
+	labels, err := c.labels(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]int64, len(labels))
+	for _, l := range labels {
+		byName[l.Name] = l.ID
+	}
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("gitea: label [%s] does not exist in repository [%s]", name, repo)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (c *giteaClient) listLabels(ctx context.Context, repo string) ([]remoteLabel, error) {
+	labels, err := c.labels(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	remote := make([]remoteLabel, 0, len(labels))
+	for _, l := range labels {
+		remote = append(remote, remoteLabel{Name: l.Name, Color: strings.TrimPrefix(l.Color, "#"), Description: l.Description})
+	}
+	return remote, nil
+}
+
+func (c *giteaClient) createLabel(ctx context.Context, repo string, label remoteLabel) error {
+	in := giteaLabel{Name: label.Name, Color: "#" + label.Color, Description: label.Description}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/labels", repo), in, nil)
+}
+
+func (c *giteaClient) searchIssues(ctx context.Context, repo, text string) ([]remoteIssue, error) {
+	var issues []remoteIssue
+	path := fmt.Sprintf("/repos/%s/issues?state=all&type=issues&limit=50&q=%s", repo, url.QueryEscape(text))
+	err := c.getPaginated(ctx, path, func(data []byte) error {
+		var page []giteaIssue
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, issue := range page {
+			if strings.Contains(issue.Title, text) {
+				issues = append(issues, issue.remote())
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+func (c *giteaClient) createIssue(ctx context.Context, repo, title, body string, labels []string) (*remoteIssue, error) {
+	ids, err := c.labelIDs(ctx, repo, labels)
+	if err != nil {
+		return nil, err
+	}
+	in := map[string]interface{}{"title": title, "body": body, "labels": ids}
+	var issue giteaIssue
+	if err := c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues", repo), in, &issue); err != nil {
+		return nil, err
+	}
+	remote := issue.remote()
+	return &remote, nil
+}
+
+func (c *giteaClient) addIssueLabels(ctx context.Context, repo string, number int, labels []string) error {
+	ids, err := c.labelIDs(ctx, repo, labels)
+	if err != nil {
+		return err
+	}
+	in := map[string]interface{}{"labels": ids}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/labels", repo, number), in, nil)
+}
+
+func (c *giteaClient) getPullRequest(ctx context.Context, repo string, number int) (*remoteIssue, error) {
+	var pr giteaIssue
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), &pr); err != nil {
+		return nil, err
+	}
+	remote := pr.remote()
+	return &remote, nil
+}
+
+func (c *giteaClient) listPullRequestFiles(ctx context.Context, repo string, number int) ([]string, error) {
+	var files []string
+	err := c.getPaginated(ctx, fmt.Sprintf("/repos/%s/pulls/%d/files?limit=50", repo, number), func(data []byte) error {
+		var page []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, f := range page {
+			files = append(files, f.Filename)
+			if f.PreviousFilename != "" {
+				files = append(files, f.PreviousFilename)
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// addPullRequestLabels uses the issues endpoint since Gitea pull requests share the issue number space
+func (c *giteaClient) addPullRequestLabels(ctx context.Context, repo string, number int, labels []string) error {
+	return c.addIssueLabels(ctx, repo, number, labels)
+}
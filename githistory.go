@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitRevision is a commit that touched the maintainers file
+type gitRevision struct {
+	hash    string
+	author  string
+	email   string
+	date    time.Time
+	subject string
+}
+
+func (r gitRevision) short() string {
+	if len(r.hash) > 7 {
+		return r.hash[:7]
+	}
+	return r.hash
+}
+
+// maintainersRevisions returns the commits that touched path, newest first
+func maintainersRevisions(path string) ([]gitRevision, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil, fmt.Errorf("error: [%s] is a directory of team files, pass the file of a team with --maintainers-file", path)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "log", "--format=%H%x00%an%x00%ae%x00%aI%x00%s", "--", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read the git history of [%s]: %s", path, strings.TrimSpace(stderr.String()))
+	}
+	var revisions []gitRevision
+	for _, line := range splitLines(string(out)) {
+		fields := strings.SplitN(line, "\x00", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("error: failed to parse the date of commit [%s]: %w", fields[0], err)
+		}
+		revisions = append(revisions, gitRevision{hash: fields[0], author: fields[1], email: fields[2], date: date, subject: fields[4]})
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("error: [%s] has no git history", path)
+	}
+	return revisions, nil
+}
+
+// maintainersAtRevision decodes the maintainers file as it was at the revision, path is relative to the current
+// directory like it is given on the command line
+func maintainersAtRevision(revision gitRevision, path string) (Maintainers, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "show", revision.hash+":./"+strings.TrimPrefix(path, "./"))
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read [%s] at commit [%s]: %s", path, revision.short(), strings.TrimSpace(stderr.String()))
+	}
+	var maintainers Maintainers
+	if err := decodeYAMLFile(bytes.NewReader(out), &maintainers); err != nil {
+		return nil, fmt.Errorf("error: failed to decode [%s] at commit [%s]: %w", path, revision.short(), err)
+	}
+	return maintainers, nil
+}
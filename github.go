@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const defaultGitHubAPIURL = "https://api.github.com"
+
+type githubClient struct {
+	*restClient
+}
+
+type githubLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type githubIssue struct {
+	Number  int           `json:"number"`
+	Title   string        `json:"title"`
+	State   string        `json:"state"`
+	HTMLURL string        `json:"html_url"`
+	Labels  []githubLabel `json:"labels"`
+}
+
+// defaultGitHubURL returns GITHUB_API_URL, which Actions sets for GitHub Enterprise, falling back to api.github.com
+func defaultGitHubURL() string {
+	if url := os.Getenv("GITHUB_API_URL"); url != "" {
+		return url
+	}
+	return defaultGitHubAPIURL
+}
+
+func newGitHubClient(baseURL, token string, transport http.RoundTripper) *githubClient {
+	header := bearerHeader("Bearer", token)
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return &githubClient{newRESTClient("github", baseURL, header, transport)}
+}
+
+func (i githubIssue) remote() remoteIssue {
+	issue := remoteIssue{Number: i.Number, Title: i.Title, URL: i.HTMLURL}
+	for _, label := range i.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue
+}
+
+func (c *githubClient) listLabels(ctx context.Context, repo string) ([]remoteLabel, error) {
+	var labels []remoteLabel
+	err := c.getPaginated(ctx, fmt.Sprintf("/repos/%s/labels?per_page=100", repo), func(data []byte) error {
+		var page []githubLabel
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, l := range page {
+			labels = append(labels, remoteLabel{Name: l.Name, Color: l.Color, Description: l.Description})
+		}
+		return nil
+	})
+	return labels, err
+}
+
+func (c *githubClient) createLabel(ctx context.Context, repo string, label remoteLabel) error {
+	in := githubLabel{Name: label.Name, Color: label.Color, Description: label.Description}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/labels", repo), in, nil)
+}
+
+func (c *githubClient) searchIssues(ctx context.Context, repo, text string) ([]remoteIssue, error) {
+	query := fmt.Sprintf("repo:%s is:issue in:title %q", repo, text)
+	var issues []remoteIssue
+	err := c.getPaginated(ctx, "/search/issues?per_page=100&q="+url.QueryEscape(query), func(data []byte) error {
+		var page struct {
+			Items []githubIssue `json:"items"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, issue := range page.Items {
+			// Search matches words anywhere in the title, keep only exact substring matches
+			if strings.Contains(issue.Title, text) {
+				issues = append(issues, issue.remote())
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+func (c *githubClient) createIssue(ctx context.Context, repo, title, body string, labels []string) (*remoteIssue, error) {
+	in := map[string]interface{}{"title": title, "body": body, "labels": labels}
+	var issue githubIssue
+	if err := c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues", repo), in, &issue); err != nil {
+		return nil, err
+	}
+	remote := issue.remote()
+	return &remote, nil
+}
+
+func (c *githubClient) addIssueLabels(ctx context.Context, repo string, number int, labels []string) error {
+	in := map[string]interface{}{"labels": labels}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/labels", repo, number), in, nil)
+}
+
+// getPullRequest reads the pull request through the issues API, which is the one that carries its labels
+func (c *githubClient) getPullRequest(ctx context.Context, repo string, number int) (*remoteIssue, error) {
+	var issue githubIssue
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/issues/%d", repo, number), &issue); err != nil {
+		return nil, err
+	}
+	remote := issue.remote()
+	return &remote, nil
+}
+
+func (c *githubClient) listPullRequestFiles(ctx context.Context, repo string, number int) ([]string, error) {
+	var files []string
+	err := c.getPaginated(ctx, fmt.Sprintf("/repos/%s/pulls/%d/files?per_page=100", repo, number), func(data []byte) error {
+		var page []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, f := range page {
+			files = append(files, f.Filename)
+			if f.PreviousFilename != "" {
+				files = append(files, f.PreviousFilename)
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (c *githubClient) addPullRequestLabels(ctx context.Context, repo string, number int, labels []string) error {
+	return c.addIssueLabels(ctx, repo, number, labels)
+}
+
+// getFileContents returns the raw contents of path in repo at ref, the raw media type also works for files
+// over the 1MB limit of the JSON representation, which most index.yaml files are
+func (c *githubClient) getFileContents(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/contents/%s?ref=%s", c.baseURL, repo, path, url.QueryEscape(ref)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (c *githubClient) createCommitStatus(ctx context.Context, repo, sha, state, statusContext, description string) error {
+	in := map[string]string{"state": state, "context": statusContext, "description": description}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/statuses/%s", repo, sha), in, nil)
+}
+
+func (c *githubClient) createComment(ctx context.Context, repo string, number int, body string) error {
+	in := map[string]string{"body": body}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number), in, nil)
+}
+
+// defaultBranch returns the branch pull requests of repo are opened against by default
+func (c *githubClient) defaultBranch(ctx context.Context, repo string) (string, error) {
+	var out struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s", repo), &out); err != nil {
+		return "", err
+	}
+	return out.DefaultBranch, nil
+}
+
+// createBranch creates branch in repo pointing at the head of base
+func (c *githubClient) createBranch(ctx context.Context, repo, branch, base string) error {
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/git/ref/heads/%s", repo, base), &ref); err != nil {
+		return err
+	}
+	in := map[string]string{"ref": "refs/heads/" + branch, "sha": ref.Object.SHA}
+	return c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/refs", repo), in, nil)
+}
+
+// commitFile commits contents to path on branch, replacing the file if it exists
+func (c *githubClient) commitFile(ctx context.Context, repo, branch, path, message string, contents []byte) error {
+	in := map[string]string{"message": message, "content": base64.StdEncoding.EncodeToString(contents), "branch": branch}
+	var existing struct {
+		SHA string `json:"sha"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/contents/%s?ref=%s", repo, path, url.QueryEscape(branch)), &existing); err == nil {
+		in["sha"] = existing.SHA
+	}
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/contents/%s", repo, path), in, nil)
+}
+
+func (c *githubClient) createPullRequest(ctx context.Context, repo, head, base, title, body string) (*remoteIssue, error) {
+	in := map[string]string{"title": title, "head": head, "base": base, "body": body}
+	var pr githubIssue
+	if err := c.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", repo), in, &pr); err != nil {
+		return nil, err
+	}
+	remote := pr.remote()
+	return &remote, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultGitLabAPIURL = "https://gitlab.com/api/v4"
+
+type gitlabClient struct {
+	*restClient
+}
+
+type gitlabLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+}
+
+type gitlabIssue struct {
+	IID    int      `json:"iid"`
+	Title  string   `json:"title"`
+	WebURL string   `json:"web_url"`
+	Labels []string `json:"labels"`
+}
+
+func newGitLabClient(baseURL, token string, transport http.RoundTripper) *gitlabClient {
+	return &gitlabClient{newRESTClient("gitlab", baseURL, bearerHeader("Bearer", token), transport)}
+}
+
+func (i gitlabIssue) remote() remoteIssue {
+	return remoteIssue{Number: i.IID, Title: i.Title, URL: i.WebURL, Labels: i.Labels}
+}
+
+// project returns the API path of a project, GitLab addresses projects by their URL-encoded full path
+func (c *gitlabClient) project(repo string) string {
+	return "/projects/" + url.PathEscape(repo)
+}
+
+func (c *gitlabClient) listLabels(ctx context.Context, repo string) ([]remoteLabel, error) {
+	var labels []remoteLabel
+	err := c.getPaginated(ctx, c.project(repo)+"/labels?per_page=100", func(data []byte) error {
+		var page []gitlabLabel
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, l := range page {
+			labels = append(labels, remoteLabel{Name: l.Name, Color: strings.TrimPrefix(l.Color, "#"), Description: l.Description})
+		}
+		return nil
+	})
+	return labels, err
+}
+
+func (c *gitlabClient) createLabel(ctx context.Context, repo string, label remoteLabel) error {
+	in := gitlabLabel{Name: label.Name, Color: "#" + label.Color, Description: label.Description}
+	return c.send(ctx, http.MethodPost, c.project(repo)+"/labels", in, nil)
+}
+
+func (c *gitlabClient) searchIssues(ctx context.Context, repo, text string) ([]remoteIssue, error) {
+	var issues []remoteIssue
+	path := fmt.Sprintf("%s/issues?per_page=100&in=title&search=%s", c.project(repo), url.QueryEscape(text))
+	err := c.getPaginated(ctx, path, func(data []byte) error {
+		var page []gitlabIssue
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, issue := range page {
+			if strings.Contains(issue.Title, text) {
+				issues = append(issues, issue.remote())
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+func (c *gitlabClient) createIssue(ctx context.Context, repo, title, body string, labels []string) (*remoteIssue, error) {
+	in := map[string]string{"title": title, "description": body, "labels": strings.Join(labels, ",")}
+	var issue gitlabIssue
+	if err := c.send(ctx, http.MethodPost, c.project(repo)+"/issues", in, &issue); err != nil {
+		return nil, err
+	}
+	remote := issue.remote()
+	return &remote, nil
+}
+
+func (c *gitlabClient) addIssueLabels(ctx context.Context, repo string, number int, labels []string) error {
+	in := map[string]string{"add_labels": strings.Join(labels, ",")}
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("%s/issues/%d", c.project(repo), number), in, nil)
+}
+
+func (c *gitlabClient) getPullRequest(ctx context.Context, repo string, number int) (*remoteIssue, error) {
+	var mr gitlabIssue
+	if err := c.get(ctx, fmt.Sprintf("%s/merge_requests/%d", c.project(repo), number), &mr); err != nil {
+		return nil, err
+	}
+	remote := mr.remote()
+	return &remote, nil
+}
+
+func (c *gitlabClient) listPullRequestFiles(ctx context.Context, repo string, number int) ([]string, error) {
+	var files []string
+	err := c.getPaginated(ctx, fmt.Sprintf("%s/merge_requests/%d/diffs?per_page=100", c.project(repo), number), func(data []byte) error {
+		var page []struct {
+			OldPath string `json:"old_path"`
+			NewPath string `json:"new_path"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		for _, f := range page {
+			files = append(files, f.NewPath)
+			if f.OldPath != f.NewPath {
+				files = append(files, f.OldPath)
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (c *gitlabClient) addPullRequestLabels(ctx context.Context, repo string, number int, labels []string) error {
+	in := map[string]string{"add_labels": strings.Join(labels, ",")}
+	return c.send(ctx, http.MethodPut, fmt.Sprintf("%s/merge_requests/%d", c.project(repo), number), in, nil)
+}
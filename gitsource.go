@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+const gitSchemePrefix = "git+"
+
+// gitFile is a file of a git repository at a ref, named by a git+ URL like
+// git+https://github.com/rancher/charts?ref=dev-v2.10&path=index.yaml. The ref is a branch, a tag or a commit and
+// defaults to the default branch of the repository
+type gitFile struct {
+	uri  string
+	repo *neturl.URL
+	ref  string
+	path string
+}
+
+// isGit reports whether uri is a git+ URL
+func isGit(uri string) bool {
+	return strings.HasPrefix(uri, gitSchemePrefix) && strings.Contains(uri, "://")
+}
+
+// parseGitFile parses a git+https://, git+http://, git+ssh:// or git+file:// URL, the file is defaultPath if the URL
+// has no path parameter
+func parseGitFile(uri, defaultPath string) (*gitFile, error) {
+	u, err := neturl.Parse(strings.TrimPrefix(uri, gitSchemePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("error: invalid git URL [%s]: %w", uri, err)
+	}
+	switch u.Scheme {
+	case "https", "http", "ssh", "file":
+	default:
+		return nil, fmt.Errorf("error: unsupported scheme of git URL [%s], use git+https://, git+http://, git+ssh:// or git+file://", uri)
+	}
+	query := u.Query()
+	f := &gitFile{uri: uri, ref: query.Get("ref"), path: strings.TrimPrefix(query.Get("path"), "/")}
+	// git would take such a ref for an option, e.g. --upload-pack running a command
+	if strings.HasPrefix(f.ref, "-") {
+		return nil, fmt.Errorf("error: invalid ref [%s] of git URL [%s], it must not start with -", f.ref, uri)
+	}
+	if f.path == "" {
+		f.path = defaultPath
+	}
+	u.RawQuery, u.Fragment = "", ""
+	f.repo = u
+	return f, nil
+}
+
+// read returns the contents of the file. Files of github.com are read through the contents API, authenticated with
+// GITHUB_TOKEN, other repositories are fetched into a temporary repository without their blobs and git then only
+// downloads the blob of the file, so neither needs a checkout of the repository
+func (f *gitFile) read(ctx context.Context) ([]byte, error) {
+	if f.repo.Scheme == "https" && f.repo.Host == "github.com" {
+		return f.readGitHub(ctx)
+	}
+	return f.readGit(ctx)
+}
+
+func (f *gitFile) readGitHub(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	repo := strings.TrimSuffix(strings.Trim(f.repo.Path, "/"), ".git")
+	if strings.Count(repo, "/") != 1 {
+		return nil, fmt.Errorf("error: git URL [%s] does not name a GitHub repository", f.uri)
+	}
+	token, err := envSecret(ctx, "GITHUB_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	client := newGitHubClient(defaultGitHubURL(), token, tracingTransport(http.DefaultTransport))
+	ref := f.ref
+	if ref == "" {
+		if ref, err = client.defaultBranch(ctx, repo); err != nil {
+			return nil, fmt.Errorf("error: failed to read [%s]: %w", f.uri, err)
+		}
+	}
+	data, err := client.getFileContents(ctx, repo, f.path, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read [%s]: %w", f.uri, err)
+	}
+	return data, nil
+}
+
+func (f *gitFile) readGit(ctx context.Context) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "cowhand-git-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	ref := f.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	// The remote is a promisor so that git show fetches the blob the filter left out, the URL, the ref and the path
+	// follow -- or --end-of-options so that git never parses them as options
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare"},
+		{"remote", "add", "--", "origin", f.repo.String()},
+		{"config", "remote.origin.promisor", "true"},
+		{"config", "remote.origin.partialclonefilter", "blob:none"},
+		{"fetch", "--quiet", "--depth=1", "--filter=blob:none", "--", "origin", ref},
+	} {
+		if out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("error: failed to fetch ref [%s] of [%s]: %s", ref, f.repo.Redacted(), strings.TrimSpace(string(out)))
+		}
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "show", "--end-of-options", "FETCH_HEAD:"+f.path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read [%s] at ref [%s] of [%s]: %s", f.path, ref, f.repo.Redacted(), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// gitIndexSource reads an index file from a git repository, index.yaml at its root if the URL has no path
+type gitIndexSource struct {
+	file *gitFile
+}
+
+func (s gitIndexSource) Load(ctx context.Context) (*repo.IndexFile, error) {
+	data, err := s.file.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodeIndex(data, s.file.uri)
+}
+
+func (s gitIndexSource) LoadNames(ctx context.Context) (*repo.IndexFile, error) {
+	data, err := s.file.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodeIndexNames(bytes.NewReader(data), s.file.uri)
+}
+
+// readGitMaintainersFile reads a maintainers file from a git repository, maintainers.yaml at its root if the URL has
+// no path
+func readGitMaintainersFile(ctx context.Context, uri string) (Maintainers, error) {
+	f, err := parseGitFile(uri, "maintainers.yaml")
+	if err != nil {
+		return nil, err
+	}
+	data, err := f.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := maintainers.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to decode maintainers file [%s]: %w", uri, err)
+	}
+	return ms, nil
+}
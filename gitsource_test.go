@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGitFile(t *testing.T) {
+	tests := []struct {
+		uri  string
+		repo string
+		ref  string
+		path string
+		err  string
+	}{
+		{uri: "git+https://github.com/rancher/charts?ref=dev-v2.10&path=index.yaml", repo: "https://github.com/rancher/charts", ref: "dev-v2.10", path: "index.yaml"},
+		{uri: "git+ssh://git@example.com/charts.git?path=/assets/index.yaml", repo: "ssh://git@example.com/charts.git", path: "assets/index.yaml"},
+		{uri: "git+file:///srv/charts", repo: "file:///srv/charts", path: "maintainers.yaml"},
+		{uri: "git+ftp://example.com/charts", err: "unsupported scheme"},
+		{uri: "git+ssh://example.com/charts?ref=--upload-pack=touch%20/tmp/pwned", err: "must not start with -"},
+		{uri: "git+file:///srv/charts?ref=-c", err: "must not start with -"},
+	}
+	for _, tt := range tests {
+		f, err := parseGitFile(tt.uri, "maintainers.yaml")
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: got error %v, want one containing %q", tt.uri, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.uri, err)
+			continue
+		}
+		if f.repo.String() != tt.repo || f.ref != tt.ref || f.path != tt.path {
+			t.Errorf("%s: got repo %s, ref %q and path %s", tt.uri, f.repo, f.ref, f.path)
+		}
+	}
+}
+
+func TestGitFileReadGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=cowhand", "GIT_AUTHOR_EMAIL=cowhand@example.com", "GIT_COMMITTER_NAME=cowhand", "GIT_COMMITTER_EMAIL=cowhand@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s", strings.Join(args, " "), out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "index.yaml"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "--quiet", "--initial-branch=main")
+	git("config", "uploadpack.allowFilter", "true")
+	write("apiVersion: v1\n")
+	git("add", "index.yaml")
+	git("commit", "--quiet", "-m", "v1")
+	git("branch", "release-v1")
+	write("apiVersion: v2\n")
+	git("commit", "--quiet", "-am", "v2")
+
+	for _, tt := range []struct{ ref, want string }{
+		{"", "apiVersion: v2\n"},
+		{"main", "apiVersion: v2\n"},
+		{"release-v1", "apiVersion: v1\n"},
+	} {
+		f, err := parseGitFile("git+file://"+dir+"?ref="+tt.ref+"&path=index.yaml", "index.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := f.read(context.Background())
+		if err != nil {
+			t.Fatalf("ref [%s]: %v", tt.ref, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("ref [%s]: got %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+	f, err := parseGitFile("git+file://"+dir+"?ref=main&path=missing.yaml", "index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.read(context.Background()); err == nil {
+		t.Error("read a missing file without an error")
+	}
+}
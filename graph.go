@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runGraph(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		format              string
+		output              string
+		crds                bool
+		dependencies        bool
+	)
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file the --dependencies are read from")
+	fs.StringVar(&format, "format", "dot", "output format, only dot is supported")
+	fs.StringVar(&output, "o", "-", "path the graph is written to, - for stdout")
+	fs.BoolVar(&crds, "crds", false, "add an edge from every chart to its -crd chart")
+	fs.BoolVar(&dependencies, "dependencies", false, "add an edge from every chart to the charts it depends on in the index, red when another team owns them")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if format != "dot" {
+		return fmt.Errorf("error: unknown format [%s], use dot", format)
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	var index *repo.IndexFile
+	if dependencies {
+		if index, err = decodeIndexFile(ctx, indexFilePath); err != nil {
+			return err
+		}
+	}
+	if output == "-" {
+		return writeOwnershipGraph(os.Stdout, maintainers, crds, index)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := writeOwnershipGraph(file, maintainers, crds, index); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeOwnershipGraph writes the teams and charts of the maintainers file as a DOT digraph with an edge from every team
+// to its charts, from every chart to its -crd chart if crds is set and to its dependencies of the index if it is
+// not nil. Team nodes are prefixed so a team and a chart sharing a name stay apart
+func writeOwnershipGraph(w io.Writer, maintainers Maintainers, crds bool, index *repo.IndexFile) error {
+	q := strconv.Quote
+	fmt.Fprintln(w, "digraph ownership {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+	seen := make(map[string]struct{})
+	for _, m := range maintainers {
+		fmt.Fprintf(w, "  %s [label=%s, shape=ellipse, style=filled, fillcolor=lightblue];\n", q("team:"+m.Name), q(m.Name))
+		for _, chart := range m.Charts {
+			if _, ok := seen[chart.Name]; !ok {
+				seen[chart.Name] = struct{}{}
+				fmt.Fprintf(w, "  %s;\n", q(chart.Name))
+			}
+			fmt.Fprintf(w, "  %s -> %s;\n", q("team:"+m.Name), q(chart.Name))
+		}
+	}
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			if _, ok := seen[chart.Name+"-crd"]; crds && ok {
+				fmt.Fprintf(w, "  %s -> %s [style=dashed, label=\"crd\"];\n", q(chart.Name), q(chart.Name+"-crd"))
+			}
+			if index == nil {
+				continue
+			}
+			cv := validate.LatestChartVersion(index, chart.Name)
+			if cv == nil {
+				continue
+			}
+			for _, d := range cv.Dependencies {
+				if d == nil {
+					continue
+				}
+				owner, _ := maintainers.FindChart(d.Name)
+				if owner == nil {
+					continue
+				}
+				color := "black"
+				if owner.Name != m.Name {
+					color = "red"
+				}
+				fmt.Fprintf(w, "  %s -> %s [style=dotted, color=%s, label=\"depends\"];\n", q(chart.Name), q(d.Name), color)
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
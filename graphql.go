@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// graphqlBatchSize bounds the number of aliased fields per query, GitHub rejects queries whose
+// node count or complexity gets too large
+const graphqlBatchSize = 50
+
+// batchReader is implemented by providers that can answer many reads in a single request, callers
+// fall back to the provider REST calls when it is not implemented
+type batchReader interface {
+	// labelsExist reports which of names exist as labels in repo
+	labelsExist(ctx context.Context, repo string, names []string) (map[string]bool, error)
+	// searchIssueTitles returns the issues in repo with each exact title, keyed by title
+	searchIssueTitles(ctx context.Context, repo string, titles []string) (map[string][]remoteIssue, error)
+}
+
+// batchReaderFor returns p as a batchReader if it can serve batched reads, GitHub only accepts authenticated
+// GraphQL requests
+func batchReaderFor(p provider) batchReader {
+	if c, ok := p.(*githubClient); ok && c.header.Get("Authorization") != "" {
+		return c
+	}
+	return nil
+}
+
+// labelsExistIn reports which of names exist as labels in repo, in a handful of GraphQL queries when possible
+// instead of listing every label in the repository
+func labelsExistIn(ctx context.Context, client provider, repo string, names []string) (map[string]bool, error) {
+	if b := batchReaderFor(client); b != nil {
+		return b.labelsExist(ctx, repo, names)
+	}
+	labels, err := client.listLabels(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(names))
+	for _, name := range names {
+		exists[name] = false
+	}
+	for _, label := range labels {
+		if _, ok := exists[label.Name]; ok {
+			exists[label.Name] = true
+		}
+	}
+	return exists, nil
+}
+
+// issuesByTitle returns the issues in repo matching each exact title, keyed by title. Without batched reads
+// it runs a single search for text, which every title must contain
+func issuesByTitle(ctx context.Context, client provider, repo, text string, titles []string) (map[string][]remoteIssue, error) {
+	if b := batchReaderFor(client); b != nil {
+		return b.searchIssueTitles(ctx, repo, titles)
+	}
+	issues, err := client.searchIssues(ctx, repo, text)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string][]remoteIssue)
+	for _, issue := range issues {
+		found[issue.Title] = append(found[issue.Title], issue)
+	}
+	return found, nil
+}
+
+// graphqlURL returns the GraphQL endpoint matching the REST base URL, GitHub Enterprise serves REST under
+// /api/v3 and GraphQL under /api/graphql
+func (c *githubClient) graphqlURL() string {
+	if strings.HasSuffix(c.baseURL, "/api/v3") {
+		return strings.TrimSuffix(c.baseURL, "/v3") + "/graphql"
+	}
+	return c.baseURL + "/graphql"
+}
+
+func (c *githubClient) graphql(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.graphqlURL(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	if len(body.Errors) > 0 {
+		var messages []string
+		for _, e := range body.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("github: graphql: %s", strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(body.Data, out)
+}
+
+func splitRepo(repo string) (string, string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("error: repository [%s] is not in owner/name form", repo)
+	}
+	return parts[0], parts[1], nil
+}
+
+func (c *githubClient) labelsExist(ctx context.Context, repo string, names []string) (map[string]bool, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(names))
+	for start := 0; start < len(names); start += graphqlBatchSize {
+		batch := names[start:min(start+graphqlBatchSize, len(names))]
+		var q strings.Builder
+		vars := map[string]interface{}{"owner": owner, "name": name}
+		q.WriteString("query($owner: String!, $name: String!")
+		for i := range batch {
+			fmt.Fprintf(&q, ", $l%d: String!", i)
+		}
+		q.WriteString(") { repository(owner: $owner, name: $name) {")
+		for i, label := range batch {
+			fmt.Fprintf(&q, " l%d: label(name: $l%d) { name }", i, i)
+			vars[fmt.Sprintf("l%d", i)] = label
+		}
+		q.WriteString(" } }")
+		var out struct {
+			Repository map[string]*struct {
+				Name string `json:"name"`
+			} `json:"repository"`
+		}
+		if err := c.graphql(ctx, q.String(), vars, &out); err != nil {
+			return nil, err
+		}
+		if out.Repository == nil {
+			return nil, fmt.Errorf("github: repository [%s] not found", repo)
+		}
+		for i, label := range batch {
+			exists[label] = out.Repository[fmt.Sprintf("l%d", i)] != nil
+		}
+	}
+	return exists, nil
+}
+
+type graphqlIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+func (c *githubClient) searchIssueTitles(ctx context.Context, repo string, titles []string) (map[string][]remoteIssue, error) {
+	found := make(map[string][]remoteIssue, len(titles))
+	for start := 0; start < len(titles); start += graphqlBatchSize {
+		batch := titles[start:min(start+graphqlBatchSize, len(titles))]
+		var q strings.Builder
+		vars := map[string]interface{}{}
+		q.WriteString("query(")
+		for i := range batch {
+			if i > 0 {
+				q.WriteString(", ")
+			}
+			fmt.Fprintf(&q, "$q%d: String!", i)
+		}
+		q.WriteString(") {")
+		for i, title := range batch {
+			fmt.Fprintf(&q, " s%d: search(type: ISSUE, query: $q%d, first: 20) { nodes { ... on Issue { number title url labels(first: 100) { nodes { name } } } } }", i, i)
+			vars[fmt.Sprintf("q%d", i)] = fmt.Sprintf("repo:%s is:issue in:title %q", repo, title)
+		}
+		q.WriteString(" }")
+		var out map[string]struct {
+			Nodes []graphqlIssue `json:"nodes"`
+		}
+		if err := c.graphql(ctx, q.String(), vars, &out); err != nil {
+			return nil, err
+		}
+		for i, title := range batch {
+			for _, node := range out[fmt.Sprintf("s%d", i)].Nodes {
+				// Search matches words anywhere in the title, keep only exact matches
+				if node.Title != title {
+					continue
+				}
+				issue := remoteIssue{Number: node.Number, Title: node.Title, URL: node.URL}
+				for _, label := range node.Labels.Nodes {
+					issue.Labels = append(issue.Labels, label.Name)
+				}
+				found[title] = append(found[title], issue)
+			}
+		}
+	}
+	return found, nil
+}
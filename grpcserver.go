@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pennyscissors/go-playground/pkg/cowhandpb"
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// grpcServer is the gRPC API of serve, answering the queries of the /v1 API from the same maintainers file and
+// validating like POST /v1/validate. api is nil when serve has no maintainers file to answer queries from, validator
+// when it has no --validate-token
+type grpcServer struct {
+	cowhandpb.UnimplementedCowhandServer
+	api       *apiServer
+	validator *validateServer
+}
+
+func (s *grpcServer) maintainers() (Maintainers, error) {
+	if s.api == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the server has no maintainers file, start it with --maintainers-file")
+	}
+	maintainers, _ := s.api.snapshot()
+	return maintainers, nil
+}
+
+func (s *grpcServer) ListTeams(ctx context.Context, req *cowhandpb.ListTeamsRequest) (*cowhandpb.ListTeamsResponse, error) {
+	maintainers, err := s.maintainers()
+	if err != nil {
+		return nil, err
+	}
+	resp := &cowhandpb.ListTeamsResponse{}
+	for _, m := range maintainers {
+		resp.Teams = append(resp.Teams, &cowhandpb.TeamSummary{Name: m.Name, Contact: contactProto(m.Contact), Charts: int32(len(m.Charts))})
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) GetTeam(ctx context.Context, req *cowhandpb.GetTeamRequest) (*cowhandpb.Team, error) {
+	maintainers, err := s.maintainers()
+	if err != nil {
+		return nil, err
+	}
+	m, _ := maintainers.FindTeamByName(req.GetName())
+	if m == nil {
+		return nil, status.Errorf(codes.NotFound, "team [%s] is not in the maintainers file", req.GetName())
+	}
+	team := &cowhandpb.Team{Name: m.Name, Contact: contactProto(m.Contact)}
+	for _, chart := range m.Charts {
+		team.Charts = append(team.Charts, &cowhandpb.Chart{
+			Name:                     chart.Name,
+			GenerateIssue:            chart.GenerateIssue,
+			GithubLabels:             chart.GithubLabels,
+			Repositories:             chart.Repositories,
+			AcknowledgedDependencies: chart.AcknowledgedDependencies,
+			MaintainedVersions:       chart.MaintainedVersions,
+		})
+	}
+	return team, nil
+}
+
+func (s *grpcServer) GetChartMaintainer(ctx context.Context, req *cowhandpb.GetChartMaintainerRequest) (*cowhandpb.ChartMaintainer, error) {
+	maintainers, err := s.maintainers()
+	if err != nil {
+		return nil, err
+	}
+	m, chart, err := s.api.findChart(maintainers, req.GetChart(), req.GetVersion())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &cowhandpb.ChartMaintainer{
+		Chart:              chart.Name,
+		Team:               m.Name,
+		Contact:            contactProto(m.Contact),
+		MaintainedVersions: chart.MaintainedVersions,
+		Repositories:       chart.Repositories,
+		GithubLabels:       chart.GithubLabels,
+	}, nil
+}
+
+func (s *grpcServer) Validate(ctx context.Context, req *cowhandpb.ValidateRequest) (*cowhandpb.ValidateResponse, error) {
+	if s.validator == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the server does not validate, start it with --validate-token")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if authorization := md.Get("authorization"); len(authorization) != 1 || !s.validator.authorized(authorization[0]) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+	if len(req.GetMaintainersFile()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the maintainers file is required")
+	}
+	results, err := s.validator.validate(ctx, req.GetMaintainersFile(), req.GetIndexFile(), req.GetIndexUrl())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, strings.TrimPrefix(err.Error(), "error: "))
+	}
+	resp := &cowhandpb.ValidateResponse{Valid: !results.HasErrors()}
+	for _, r := range results {
+		severity := cowhandpb.Severity_SEVERITY_ERROR
+		if r.Severity == validate.SeverityWarning {
+			severity = cowhandpb.Severity_SEVERITY_WARNING
+		}
+		resp.Findings = append(resp.Findings, &cowhandpb.Finding{Rule: r.Rule, Severity: severity, Chart: r.Chart, Message: r.Message})
+	}
+	return resp, nil
+}
+
+func contactProto(c Contact) *cowhandpb.Contact {
+	return &cowhandpb.Contact{Email: c.Email, SlackChannel: c.SlackChannel, Url: c.URL}
+}
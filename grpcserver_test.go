@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pennyscissors/go-playground/pkg/cowhandpb"
+)
+
+func TestGRPCServerValidateAuthorization(t *testing.T) {
+	req := &cowhandpb.ValidateRequest{MaintainersFile: []byte("- name: team-a\n  contact:\n    email: a@example.com\n")}
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	if _, err := (&grpcServer{}).Validate(withToken("token"), req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("without --validate-token: got %v, want %s", err, codes.FailedPrecondition)
+	}
+	s := &grpcServer{validator: &validateServer{config: &Config{}, token: []byte("token")}}
+	for _, ctx := range []context.Context{context.Background(), withToken("other")} {
+		if _, err := s.Validate(ctx, req); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("got %v, want %s", err, codes.Unauthenticated)
+		}
+	}
+	resp, err := s.Validate(withToken("token"), req)
+	if err != nil || !resp.GetValid() {
+		t.Errorf("got %v and %v, want a valid maintainers file", resp, err)
+	}
+}
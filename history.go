@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func runHistory(ctx context.Context, args []string) error {
+	var maintainersFilePath string
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file, which must be tracked by git")
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" {
+		return errors.New("error: usage: cowhand history <chart>")
+	}
+
+	revisions, err := maintainersRevisions(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tCOMMIT\tAUTHOR\tCHANGE")
+	owner, changes := "", 0
+	// Oldest first, a revision is only printed when it changes the team owning the chart
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revision := revisions[i]
+		maintainers, err := maintainersAtRevision(revision, maintainersFilePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v, skipping it\n", err)
+			continue
+		}
+		change, current := ownerChange(maintainers, chartName, owner)
+		if change == "" {
+			continue
+		}
+		owner = current
+		changes++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", revision.date.Format("2006-01-02"), revision.short(), revision.author, change)
+	}
+	if maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath); err == nil {
+		if change, _ := ownerChange(maintainers, chartName, owner); change != "" {
+			changes++
+			fmt.Fprintf(w, "-\tuncommitted\t-\t%s\n", change)
+		}
+	}
+	if changes == 0 {
+		return fmt.Errorf("error: chart [%s] was never in the git history of [%s]", chartName, maintainersFilePath)
+	}
+	return w.Flush()
+}
+
+// ownerChange describes how the owner of the chart in maintainers differs from previous, "" for none, returning the
+// new owner alongside it
+func ownerChange(maintainers Maintainers, chartName, previous string) (string, string) {
+	current := ""
+	if m, _ := maintainers.FindChart(chartName); m != nil {
+		current = m.Name
+	}
+	switch {
+	case current == previous:
+		return "", current
+	case previous == "":
+		return fmt.Sprintf("added to [%s]", current), current
+	case current == "":
+		return fmt.Sprintf("removed from [%s]", previous), current
+	}
+	return fmt.Sprintf("moved from [%s] to [%s]", previous, current), current
+}
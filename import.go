@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ownershipRow is a line of an ownership csv, fields whose column is missing or empty are left as they are
+type ownershipRow struct {
+	line          int
+	chart         string
+	team          string
+	email         string
+	slack         string
+	url           string
+	labels        []string
+	hasLabels     bool
+	generateIssue *bool
+}
+
+func runImport(ctx context.Context, args []string) error {
+	if len(args) < 2 || args[0] != "csv" || strings.HasPrefix(args[1], "-") {
+		return errors.New("error: usage: cowhand import csv <ownership.csv> [--apply]")
+	}
+	csvPath := args[1]
+	var (
+		maintainersFilePath string
+		apply               bool
+	)
+	fs := flag.NewFlagSet("import csv", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file to create or update")
+	fs.BoolVar(&apply, "apply", false, "write the changes instead of only printing the diff")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	fs.Parse(args[2:])
+
+	rows, err := readOwnershipCSV(csvPath)
+	if err != nil {
+		return err
+	}
+	// A missing maintainers file is created from the csv alone
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.SequenceNode, Tag: "!!seq"}}}
+	} else if err != nil {
+		return err
+	}
+	changes, err := importOwnership(doc, rows)
+	if err != nil {
+		return err
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	diff := unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated)
+	if diff == "" {
+		fmt.Printf("maintainers file [%s] already matches [%s]\n", maintainersFilePath, csvPath)
+		return nil
+	}
+	fmt.Print(diff)
+	if !apply {
+		fmt.Println("\nRe-run with --apply to write these changes")
+		return nil
+	}
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Println()
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	return nil
+}
+
+// readOwnershipCSV reads the rows of an ownership csv whose header names its columns: chart, team, email, slack,
+// url, labels and generateIssue in any order, only chart and team are required. Labels are separated by commas or
+// semicolons. A chart listed for two teams or a team with two emails or slack channels is an error
+func readOwnershipCSV(path string) ([]ownershipRow, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read the header of [%s]: %w", path, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"chart", "team"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("error: [%s] has no [%s] column", path, required)
+		}
+	}
+	var rows []ownershipRow
+	owners := make(map[string]ownershipRow)
+	emails := make(map[string]ownershipRow)
+	slacks := make(map[string]ownershipRow)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error: failed to read [%s]: %w", path, err)
+		}
+		// The reader skips empty lines, so the line is read from it rather than counted
+		line, _ := r.FieldPos(0)
+		cell := func(column string) (string, bool) {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return "", false
+			}
+			return strings.TrimSpace(record[i]), true
+		}
+		row := ownershipRow{line: line}
+		row.chart, _ = cell("chart")
+		row.team, _ = cell("team")
+		if row.chart == "" && row.team == "" {
+			continue
+		}
+		if row.chart == "" || row.team == "" {
+			return nil, fmt.Errorf("error: line %d of [%s] needs both a chart and a team", line, path)
+		}
+		row.email, _ = cell("email")
+		row.slack, _ = cell("slack")
+		row.url, _ = cell("url")
+		if labels, _ := cell("labels"); labels != "" {
+			row.hasLabels = true
+			row.labels = []string{}
+			for _, label := range strings.FieldsFunc(labels, func(r rune) bool { return r == ',' || r == ';' }) {
+				if label = strings.TrimSpace(label); label != "" {
+					row.labels = append(row.labels, label)
+				}
+			}
+		}
+		if value, _ := cell("generateissue"); value != "" {
+			generateIssue, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("error: line %d of [%s] has invalid generateIssue [%s]", line, path, value)
+			}
+			row.generateIssue = &generateIssue
+		}
+		if previous, ok := owners[row.chart]; ok && previous.team != row.team {
+			return nil, fmt.Errorf("error: chart [%s] is owned by [%s] on line %d and by [%s] on line %d of [%s]", row.chart, previous.team, previous.line, row.team, line, path)
+		}
+		owners[row.chart] = row
+		if previous, ok := emails[row.team]; ok && row.email != "" && row.email != previous.email {
+			return nil, fmt.Errorf("error: team [%s] has email [%s] on line %d and [%s] on line %d of [%s]", row.team, previous.email, previous.line, row.email, line, path)
+		} else if !ok && row.email != "" {
+			emails[row.team] = row
+		}
+		if previous, ok := slacks[row.team]; ok && row.slack != "" && row.slack != previous.slack {
+			return nil, fmt.Errorf("error: team [%s] has slack channel [%s] on line %d and [%s] on line %d of [%s]", row.team, previous.slack, previous.line, row.slack, line, path)
+		} else if !ok && row.slack != "" {
+			slacks[row.team] = row
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importOwnership creates or updates the teams and charts of the rows in doc, charts owned by another team are
+// moved to the team of their row. It returns the changes it made
+func importOwnership(doc *yaml.Node, rows []ownershipRow) ([]string, error) {
+	var maintainers Maintainers
+	if err := doc.Decode(&maintainers); err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, row := range rows {
+		if m, _ := maintainers.FindTeamByName(row.team); m == nil {
+			doc.Content[0].Content = append(doc.Content[0].Content, newTeamNode(row.team))
+			maintainers = append(maintainers, &Maintainer{Name: row.team})
+			changes = append(changes, fmt.Sprintf("created team [%s]", row.team))
+		}
+		fields := make(map[string]string)
+		if row.email != "" {
+			fields["email"] = row.email
+		}
+		if row.slack != "" {
+			fields["slackChannel"] = row.slack
+		}
+		if row.url != "" {
+			fields["url"] = row.url
+		}
+		var teamNode *yaml.Node
+		for _, t := range teamNodes(doc) {
+			if nodeName(t) == row.team {
+				teamNode = t
+			}
+		}
+		if len(fields) > 0 {
+			setContactNode(teamNode, fields)
+		}
+
+		var chartNode *yaml.Node
+		switch owner, _ := maintainers.FindChart(row.chart); {
+		case owner == nil:
+			chart := Chart{Name: row.chart, GithubLabels: []string{}}
+			var value yaml.Node
+			if err := value.Encode(chart); err != nil {
+				return nil, err
+			}
+			if err := insertChartNode(doc, row.team, row.chart, &value); err != nil {
+				return nil, err
+			}
+			chartNode = &value
+			changes = append(changes, fmt.Sprintf("added chart [%s] to [%s]", row.chart, row.team))
+		case owner.Name != row.team:
+			nodes := extractChartNodes(doc, func(team, chart string) bool { return chart == row.chart })
+			for _, node := range nodes {
+				if err := insertChartNode(doc, row.team, row.chart, node); err != nil {
+					return nil, err
+				}
+			}
+			chartNode = nodes[0]
+			changes = append(changes, fmt.Sprintf("moved chart [%s] from [%s] to [%s]", row.chart, owner.Name, row.team))
+		default:
+			charts := mappingValue(teamNode, "charts")
+			for _, c := range charts.Content {
+				if nodeName(c) == row.chart && chartNode == nil {
+					chartNode = c
+				}
+			}
+		}
+		// The in memory maintainers follow the file so later rows see the charts of earlier ones
+		if err := doc.Decode(&maintainers); err != nil {
+			return nil, err
+		}
+		// generateIssue defaults to false, it is not added to charts that do not set it just to say so
+		if current := mappingValue(chartNode, "generateIssue"); row.generateIssue != nil && (current != nil || *row.generateIssue) {
+			setMappingValue(chartNode, "generateIssue", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(*row.generateIssue)})
+		}
+		if row.hasLabels {
+			var labels yaml.Node
+			if err := labels.Encode(row.labels); err != nil {
+				return nil, err
+			}
+			formatSequenceStyle(&labels)
+			if existing := mappingValue(chartNode, "githubLabels"); existing != nil && equalLabels(existing, row.labels) {
+				continue
+			}
+			setMappingValue(chartNode, "githubLabels", &labels)
+		}
+	}
+	return changes, nil
+}
+
+// equalLabels reports whether a githubLabels node holds exactly labels
+func equalLabels(node *yaml.Node, labels []string) bool {
+	if node.Kind != yaml.SequenceNode || len(node.Content) != len(labels) {
+		return false
+	}
+	for i, label := range node.Content {
+		if label.Value != labels[i] {
+			return false
+		}
+	}
+	return true
+}
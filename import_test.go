@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadOwnershipCSVErrors(t *testing.T) {
+	tests := []struct {
+		csv string
+		err string
+	}{
+		{csv: "chart,email\nfleet,a@example.com\n", err: "has no [team] column"},
+		{csv: "chart,team\nfleet,\n", err: "line 2 of"},
+		{csv: "chart,team,generateIssue\nfleet,team-a,maybe\n", err: "invalid generateIssue [maybe]"},
+		{csv: "chart,team\nfleet,team-a\n\nfleet,team-b\n", err: "chart [fleet] is owned by [team-a] on line 2 and by [team-b] on line 4"},
+		{csv: "chart,team,email\nfleet,team-a,a@example.com\nfleet-crd,team-a,b@example.com\n", err: "team [team-a] has email [a@example.com] on line 2 and [b@example.com] on line 3"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "ownership.csv")
+		if err := os.WriteFile(path, []byte(tt.csv), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readOwnershipCSV(path); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q: got error %v, want one containing %q", tt.csv, err, tt.err)
+		}
+	}
+}
+
+func TestImportOwnership(t *testing.T) {
+	dir := t.TempDir()
+	maintainersPath := filepath.Join(dir, "maintainers.yaml")
+	existing := `- name: team-a
+  contact:
+    email: a@example.com
+  charts:
+    - name: fleet
+      githubLabels: [area/fleet]
+      generateIssue: true
+    - name: rancher-monitoring
+`
+	if err := os.WriteFile(maintainersPath, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The header is matched ignoring case, spaces and the byte order mark spreadsheets write
+	csvPath := filepath.Join(dir, "ownership.csv")
+	ownership := "\ufeffChart, Team ,Slack,Labels,GenerateIssue\n" +
+		"fleet,team-b,#team-b,area/fleet;team/b,false\n" +
+		"rancher-monitoring,team-a,,,\n" +
+		"rancher-logging,team-b,,,true\n"
+	if err := os.WriteFile(csvPath, []byte(ownership), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := readOwnershipCSV(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, _, err := decodeMaintainersNode(maintainersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := importOwnership(doc, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantChanges := []string{
+		"created team [team-b]",
+		"moved chart [fleet] from [team-a] to [team-b]",
+		"added chart [rancher-logging] to [team-b]",
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("got changes %q, want %q", changes, wantChanges)
+	}
+	var got Maintainers
+	if err := doc.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := Maintainers{
+		{Name: "team-a", Contact: Contact{Email: "a@example.com"}, Charts: []Chart{{Name: "rancher-monitoring"}}},
+		{Name: "team-b", Contact: Contact{SlackChannel: "#team-b"}, Charts: []Chart{
+			{Name: "fleet", GithubLabels: []string{"area/fleet", "team/b"}},
+			{Name: "rancher-logging", GithubLabels: []string{}, GenerateIssue: true},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got teams %+v, want %+v", got, want)
+	}
+
+	// Importing the same csv again changes nothing
+	changes, err = importOwnership(doc, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("second import made changes %q", changes)
+	}
+}
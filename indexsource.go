@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	yaml "gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+const (
+	fileScheme      = "file://"
+	helmCacheScheme = "helm-cache://"
+)
+
+// IndexSource is where the index of a chart repository is loaded from, the rules only see the loaded index
+type IndexSource interface {
+	Load(ctx context.Context) (*repo.IndexFile, error)
+}
+
+// indexNamesSource is implemented by the sources that can load an index holding only the name and version of every
+// chart version, which is all the commands that only look at chart names need and a fraction of a large index
+type indexNamesSource interface {
+	LoadNames(ctx context.Context) (*repo.IndexFile, error)
+}
+
+// newIndexSource selects the source of the index by the scheme of uri: http(s):// downloads it, s3:// and gs:// read
+// it from a bucket, git+ reads it from a git repository at a ref, oci:// lists the charts of a registry,
+// helm-cache://<repository> reads the index helm cached for a repository and file:// or no scheme reads a local
+// index file or generates one from a directory of charts
+func newIndexSource(uri string) (IndexSource, error) {
+	switch {
+	case isOCI(uri):
+		return ociIndexSource{ref: uri}, nil
+	case isRemote(uri):
+		return httpIndexSource{url: uri}, nil
+	case isObject(uri):
+		if _, _, err := splitObject(uri); err != nil {
+			return nil, err
+		}
+		return objectIndexSource{uri: uri}, nil
+	case isGit(uri):
+		file, err := parseGitFile(uri, "index.yaml")
+		if err != nil {
+			return nil, err
+		}
+		return gitIndexSource{file: file}, nil
+	case strings.HasPrefix(uri, helmCacheScheme):
+		name := strings.TrimPrefix(uri, helmCacheScheme)
+		if name == "" {
+			return nil, fmt.Errorf("error: index [%s] does not name a helm repository, use helm-cache://<repository>", uri)
+		}
+		return helmCacheIndexSource{repository: name}, nil
+	case strings.HasPrefix(uri, fileScheme):
+		return localIndexSource{path: strings.TrimPrefix(uri, fileScheme)}, nil
+	case strings.Contains(uri, "://"):
+		return nil, fmt.Errorf("error: unsupported scheme of index [%s], use a path, file://, http(s)://, s3://, gs://, git+https://, oci:// or helm-cache://", uri)
+	}
+	return localIndexSource{path: uri}, nil
+}
+
+// localIndexSource reads an index file, or generates the index of a directory of charts like helm repo index does
+type localIndexSource struct {
+	path string
+}
+
+func (s localIndexSource) Load(ctx context.Context) (*repo.IndexFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fsys, name := localFS(s.path)
+	if info, err := fs.Stat(fsys, name); err == nil && info.IsDir() {
+		index, err := generateIndex(fsys, name)
+		return index, localPathError(err, s.path)
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, localPathError(err, s.path)
+	}
+	return decodeIndex(data, s.path)
+}
+
+func (s localIndexSource) LoadNames(ctx context.Context) (*repo.IndexFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fsys, name := localFS(s.path)
+	if info, err := fs.Stat(fsys, name); err == nil && info.IsDir() {
+		return s.Load(ctx)
+	}
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, localPathError(err, s.path)
+	}
+	defer file.Close()
+	return decodeIndexNames(file, s.path)
+}
+
+// httpIndexSource downloads an index file, e.g. https://charts.rancher.io/index.yaml. uncached downloads it past
+// the index cache, for the URLs the requests of serve name, which must not fill the disk of the server
+type httpIndexSource struct {
+	url      string
+	uncached bool
+}
+
+func (s httpIndexSource) client() *http.Client {
+	if s.uncached {
+		return remoteClient
+	}
+	return indexClient()
+}
+
+func (s httpIndexSource) Load(ctx context.Context) (*repo.IndexFile, error) {
+	data, err := fetch(ctx, s.client(), s.url)
+	if err != nil {
+		return nil, err
+	}
+	return decodeIndex(data, s.url)
+}
+
+// LoadNames decodes the index as it is downloaded, without holding the whole file
+func (s httpIndexSource) LoadNames(ctx context.Context) (*repo.IndexFile, error) {
+	body, err := openFetch(ctx, s.client(), s.url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return decodeIndexNames(body, s.url)
+}
+
+// helmCacheIndexSource reads the index helm cached for a repository on helm repo add or update
+type helmCacheIndexSource struct {
+	repository string
+}
+
+func (s helmCacheIndexSource) Load(ctx context.Context) (*repo.IndexFile, error) {
+	path, err := helmCacheIndexFile(s.repository)
+	if err != nil {
+		return nil, err
+	}
+	return localIndexSource{path: path}.Load(ctx)
+}
+
+func (s helmCacheIndexSource) LoadNames(ctx context.Context) (*repo.IndexFile, error) {
+	path, err := helmCacheIndexFile(s.repository)
+	if err != nil {
+		return nil, err
+	}
+	return localIndexSource{path: path}.LoadNames(ctx)
+}
+
+// ociIndexSource lists the charts of an OCI registry namespace as an index, see listOCIIndex
+type ociIndexSource struct {
+	ref string
+}
+
+func (s ociIndexSource) Load(ctx context.Context) (*repo.IndexFile, error) {
+	return listOCIIndex(ctx, s.ref)
+}
+
+// decodeIndexNamesFile is decodeIndexFile for the commands that only need the names of the charts, the versions of
+// the index only hold their name and version when the source supports it
+func decodeIndexNamesFile(ctx context.Context, path string) (index *repo.IndexFile, err error) {
+	ctx, span := startSpan(ctx, "load index names", attribute.String("cowhand.index", path))
+	defer func() { endSpan(span, err) }()
+	source, err := newIndexSource(path)
+	if err != nil {
+		return nil, err
+	}
+	if names, ok := source.(indexNamesSource); ok {
+		return names.LoadNames(ctx)
+	}
+	return source.Load(ctx)
+}
+
+// indexNames is the part of an index decodeIndexNames keeps, the names and versions of the chart versions and the
+// fields dropInvalidEntries checks so that it drops the same entries as for a whole index
+type indexNames struct {
+	APIVersion string `yaml:"apiVersion"`
+	Entries    map[string][]*struct {
+		Name         string              `yaml:"name"`
+		Version      string              `yaml:"version"`
+		APIVersion   string              `yaml:"apiVersion"`
+		Type         string              `yaml:"type"`
+		Maintainers  []*chart.Maintainer `yaml:"maintainers"`
+		Dependencies []*struct {
+			Name  string `yaml:"name"`
+			Alias string `yaml:"alias"`
+		} `yaml:"dependencies"`
+	} `yaml:"entries"`
+}
+
+// decodeIndexNames decodes the index from r keeping only the name and version of every chart version. It is decoded
+// with a yaml.Decoder, which also reads JSON, straight from r into indexNames rather than through a copy of the file
+// converted to JSON, so only the names and versions are retained
+func decodeIndexNames(r io.Reader, source string) (*repo.IndexFile, error) {
+	var decoded indexNames
+	if err := yaml.NewDecoder(r).Decode(&decoded); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, repo.ErrEmptyIndexYaml
+		}
+		return nil, fmt.Errorf("error loading %s: %w", source, err)
+	}
+	if decoded.APIVersion == "" {
+		return nil, fmt.Errorf("error loading %s: %w", source, repo.ErrNoAPIVersion)
+	}
+	index := &repo.IndexFile{APIVersion: decoded.APIVersion, Entries: make(map[string]repo.ChartVersions, len(decoded.Entries))}
+	for name, versions := range decoded.Entries {
+		cvs := make(repo.ChartVersions, 0, len(versions))
+		for _, v := range versions {
+			if v == nil {
+				cvs = append(cvs, nil)
+				continue
+			}
+			metadata := &chart.Metadata{Name: v.Name, Version: v.Version, APIVersion: v.APIVersion, Type: v.Type, Maintainers: v.Maintainers}
+			for _, d := range v.Dependencies {
+				if d == nil {
+					metadata.Dependencies = append(metadata.Dependencies, nil)
+					continue
+				}
+				metadata.Dependencies = append(metadata.Dependencies, &chart.Dependency{Name: d.Name, Alias: d.Alias})
+			}
+			cvs = append(cvs, &repo.ChartVersion{Metadata: metadata})
+		}
+		index.Entries[name] = cvs
+	}
+	dropInvalidEntries(index, source)
+	// Only the name and version are kept once the entries are checked
+	for _, versions := range index.Entries {
+		for _, cv := range versions {
+			cv.Metadata = &chart.Metadata{Name: cv.Name, Version: cv.Version}
+		}
+	}
+	index.SortEntries()
+	return index, nil
+}
+
+// helmCacheIndexFile returns the path of the index helm cached for a repository on helm repo add or update,
+// HELM_REPOSITORY_CACHE overrides the default cache directory the same way it does for helm
+func helmCacheIndexFile(name string) (string, error) {
+	cacheDir := os.Getenv("HELM_REPOSITORY_CACHE")
+	if cacheDir == "" {
+		cacheDir = helmpath.CachePath("repository")
+	}
+	path := filepath.Join(cacheDir, helmpath.CacheIndexFile(name))
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("error: no cached index for helm repository [%s] in [%s], run helm repo add or helm repo update first", name, cacheDir)
+	}
+	return path, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func TestDecodeIndexNamesDropsInvalidEntries(t *testing.T) {
+	data := `apiVersion: v1
+entries:
+  fleet:
+    - name: fleet
+      version: 104.0.0
+    - null
+    - name: fleet
+    - name: fleet
+      version: not-semver
+    - name: fleet
+      version: 103.1.0
+      type: plugin
+  rancher-monitoring:
+    - name: rancher-monitoring
+      version: 104.1.0
+      dependencies:
+        - name: grafana
+          alias: not/an/alias
+    - name: rancher-monitoring
+      version: 104.0.0
+      maintainers:
+        - null
+    - name: rancher-monitoring
+      version: 103.0.0
+      dependencies:
+        - name: grafana
+        - name: grafana
+  nameless:
+    - version: 1.0.0
+`
+	versions := func(index *repo.IndexFile) map[string][]string {
+		got := make(map[string][]string)
+		for name, cvs := range index.Entries {
+			for _, cv := range cvs {
+				got[name] = append(got[name], cv.Name+"@"+cv.Version)
+			}
+		}
+		return got
+	}
+	index, err := decodeIndex([]byte(data), "index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := decodeIndexNames(strings.NewReader(data), "index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Duplicate dependencies are kept like helm keeps them, every other invalid entry is dropped
+	want := map[string][]string{
+		"fleet":              {"fleet@104.0.0"},
+		"rancher-monitoring": {"rancher-monitoring@103.0.0"},
+	}
+	if got := versions(index); !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeIndex kept %v, want %v", got, want)
+	}
+	if got := versions(names); !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeIndexNames kept %v, want %v", got, want)
+	}
+}
+
+func TestHTTPIndexSourceLoadNamesCached(t *testing.T) {
+	// The index is served compressed, and for an hour so that only the first load downloads it
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	io.WriteString(gz, "apiVersion: v1\nentries:\n  fleet:\n    - name: fleet\n      version: 104.0.0\n      description: "+strings.Repeat("x", 64<<10)+"\n")
+	gz.Close()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write(gzipped.Bytes())
+	}))
+	defer server.Close()
+	defer func(dir string) { indexCache.dir = dir }(indexCache.dir)
+	indexCache.dir = t.TempDir()
+
+	source := httpIndexSource{url: server.URL + "/index.yaml.gz"}
+	for i := 0; i < 2; i++ {
+		index, err := source.LoadNames(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cvs := index.Entries["fleet"]; len(cvs) != 1 || cvs[0].Version != "104.0.0" {
+			t.Fatalf("load %d: got entries %v", i+1, index.Entries)
+		}
+	}
+	if _, err := source.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("got %d requests, want the names decoded as they were downloaded to be cached", requests.Load())
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// unassignedTeam holds the charts no team has claimed yet
+const unassignedTeam = "UNASSIGNED"
+
+func runInit(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		force               bool
+	)
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path of the maintainers file to create")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.BoolVar(&force, "force", false, "overwrite the maintainers file if it already exists")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+
+	if _, err := os.Stat(maintainersFilePath); err == nil && !force {
+		return fmt.Errorf("error: maintainers file [%s] already exists, use --force to overwrite it", maintainersFilePath)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	index, err := decodeIndexNamesFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	team := &Maintainer{Name: unassignedTeam}
+	for _, name := range validate.IndexChartNames(index) {
+		team.Charts = append(team.Charts, Chart{Name: name, GithubLabels: []string{}})
+	}
+	var teams yaml.Node
+	if err := teams.Encode(Maintainers{team}); err != nil {
+		return err
+	}
+	doc := yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&teams}}
+	formatMaintainersNode(&doc)
+	data, err := encodeMaintainersNode(&doc, nil)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(maintainersFilePath, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("created [%s] with %s under team [%s]\n", maintainersFilePath, pluralize(len(team.Charts), "chart"), unassignedTeam)
+	return nil
+}
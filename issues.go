@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+const defaultLabelColor = "ededed"
+
+func runIssues(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		repo                string
+		release             string
+		helmRepo            string
+		apply               bool
+		notify              bool
+		email               bool
+		configFilePath      string
+		pf                  providerFlags
+	)
+	fs := flag.NewFlagSet("issues", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file used to enrich issue bodies")
+	fs.StringVar(&helmRepo, "from-helm-cache", "", "name of a repository added with helm repo add to read the cached index of, overrides --index-file")
+	fs.StringVar(&repo, "repo", "", "owner/name of the repository issues are created in")
+	fs.StringVar(&release, "release", "", "release the tracking issues are created for, e.g. v2.9.0")
+	fs.BoolVar(&apply, "apply", false, "execute the plan instead of only printing it")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	fs.BoolVar(&notify, "notify-teams", false, "with --apply, post the tracking issues created for the charts of every team to its teamNotifications in the config file, or else its Slack channel")
+	fs.BoolVar(&email, "email-teams", false, "with --apply, email every team a digest of the tracking issues created for its charts, through the SMTP server of the email of the config file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the team notifications of --notify-teams and the email settings of --email-teams")
+	pf.register(fs)
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if repo == "" || release == "" {
+		return errors.New("error: both --repo and --release are required")
+	}
+	if helmRepo != "" {
+		var err error
+		if indexFilePath, err = helmCacheIndexFile(helmRepo); err != nil {
+			return err
+		}
+	}
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	index, err := decodeIndexFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	client, err := pf.provider(ctx)
+	if err != nil {
+		return err
+	}
+	created := make(map[*Maintainer][]*remoteIssue)
+	p, err := planIssues(ctx, client, maintainers, index, repo, release, func(m *Maintainer, issue *remoteIssue) {
+		created[m] = append(created[m], issue)
+	})
+	if err != nil {
+		return err
+	}
+	p.print(os.Stdout)
+	if p.empty() {
+		return nil
+	}
+	if !apply {
+		fmt.Println("\nNothing was changed, run again with --apply to execute this plan")
+		return nil
+	}
+	fmt.Println()
+	// The issues created before a failure are still notified
+	errs := []error{p.apply(os.Stdout)}
+	messages := teamIssues(maintainers, created, repo, release)
+	if notify {
+		errs = append(errs, notifyTeams(ctx, config, messages))
+	}
+	if email {
+		errs = append(errs, emailTeams(ctx, config, messages))
+	}
+	return errors.Join(errs...)
+}
+
+// planIssues compares the tracking issues and labels the maintainers file requires with what already exists in repo,
+// created is called with every issue the plan creates when it is applied
+func planIssues(ctx context.Context, client provider, maintainers Maintainers, index *repo.IndexFile, repo, release string, created func(*Maintainer, *remoteIssue)) (*plan, error) {
+	p := &plan{}
+
+	var labels, titles []string
+	seenLabels := make(map[string]struct{})
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			if !tracksIssue(chart, index) {
+				continue
+			}
+			titles = append(titles, issueTitle(release, chart.Name))
+			for _, label := range chart.GithubLabels {
+				if _, ok := seenLabels[label]; !ok {
+					seenLabels[label] = struct{}{}
+					labels = append(labels, label)
+				}
+			}
+		}
+	}
+
+	existingLabels, err := labelsExistIn(ctx, client, repo, labels)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range labels {
+		if existingLabels[name] {
+			continue
+		}
+		label := remoteLabel{Name: name, Color: defaultLabelColor}
+		p.add(planCreate, "label", fmt.Sprintf("[%s]", label.Name), func() error {
+			return client.createLabel(ctx, repo, label)
+		})
+	}
+
+	existingIssues, err := issuesByTitle(ctx, client, repo, "["+release+"]", titles)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			if !tracksIssue(chart, index) {
+				continue
+			}
+			latest := validate.LatestChartVersion(index, chart.Name)
+			title := issueTitle(release, chart.Name)
+			issues, ok := existingIssues[title]
+			if !ok {
+				body := issueBody(m, chart, latest, release)
+				labels := chart.GithubLabels
+				p.add(planCreate, "issue", fmt.Sprintf("%q labels %v", title, labels), func() error {
+					issue, err := client.createIssue(ctx, repo, title, body, labels)
+					if err == nil {
+						created(m, issue)
+					}
+					return err
+				})
+				continue
+			}
+			issue := issues[0]
+			missing := missingIssueLabels(issue, chart.GithubLabels)
+			if len(missing) == 0 {
+				p.add(planSkip, "issue", fmt.Sprintf("#%d %q", issue.Number, title), nil)
+				continue
+			}
+			number := issue.Number
+			p.add(planUpdate, "issue", fmt.Sprintf("#%d %q add labels %v", number, title, missing), func() error {
+				return client.addIssueLabels(ctx, repo, number, missing)
+			})
+		}
+	}
+	return p, nil
+}
+
+// tracksIssue reports whether a tracking issue is created for the chart, charts split across teams by version range
+// are tracked by the team maintaining the latest version
+func tracksIssue(chart Chart, index *repo.IndexFile) bool {
+	if !chart.GenerateIssue {
+		return false
+	}
+	latest := validate.LatestChartVersion(index, chart.Name)
+	return latest == nil || chart.Maintains(latest.Version)
+}
+
+func issueTitle(release, chartName string) string {
+	return fmt.Sprintf("[%s] %s", release, chartName)
+}
+
+// issueBody describes the chart with its latest version from the index so the issue can be acted on without
+// cross-referencing index.yaml, latest is nil for charts that are not in the index yet
+func issueBody(m *Maintainer, chart Chart, latest *repo.ChartVersion, release string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tracking issue for chart `%s` in release `%s`.\n\n", chart.Name, release)
+	if latest != nil {
+		fmt.Fprintf(&b, "| | |\n|---|---|\n")
+		fmt.Fprintf(&b, "| Latest version | `%s` |\n", latest.Version)
+		if latest.AppVersion != "" {
+			fmt.Fprintf(&b, "| App version | `%s` |\n", latest.AppVersion)
+		}
+		if upstream := upstreamURL(latest); upstream != "" {
+			fmt.Fprintf(&b, "| Upstream | %s |\n", upstream)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Maintained by: %s\n", m.Name)
+	return b.String()
+}
+
+func missingIssueLabels(issue remoteIssue, labels []string) []string {
+	has := make(map[string]struct{}, len(issue.Labels))
+	for _, label := range issue.Labels {
+		has[label] = struct{}{}
+	}
+	var missing []string
+	for _, label := range labels {
+		if _, ok := has[label]; !ok {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
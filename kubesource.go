@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	neturl "net/url"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+const (
+	configMapScheme = "configmap://"
+	secretScheme    = "secret://"
+)
+
+// kubeObject is a key of a ConfigMap or a Secret, named by a URL like configmap://cattle-system/maintainers or
+// secret://slack?key=token. The namespace defaults to that of the kubeconfig, which is the namespace of the pod
+// in-cluster
+type kubeObject struct {
+	uri       string
+	namespace string
+	name      string
+	key       string
+}
+
+// parseKubeObject parses a configmap:// or secret:// URL, the key is defaultKey if the URL has no key parameter
+func parseKubeObject(uri, defaultKey string) (kubeObject, error) {
+	scheme, rest, _ := strings.Cut(uri, "://")
+	rest, query, _ := strings.Cut(rest, "?")
+	values, err := neturl.ParseQuery(query)
+	if err != nil {
+		return kubeObject{}, fmt.Errorf("error: invalid %s URL [%s]: %w", scheme, uri, err)
+	}
+	o := kubeObject{uri: uri, key: values.Get("key")}
+	if o.key == "" {
+		o.key = defaultKey
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		o.name = parts[0]
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		o.namespace, o.name = parts[0], parts[1]
+	default:
+		return kubeObject{}, fmt.Errorf("error: [%s] does not name a %s, use %s[<namespace>/]<name>", uri, scheme, scheme+"://")
+	}
+	if o.key == "" {
+		return kubeObject{}, fmt.Errorf("error: [%s] does not name a key, add ?key=<key>", uri)
+	}
+	return o, nil
+}
+
+var kubeClient struct {
+	once      sync.Once
+	clientset *kubernetes.Clientset
+	namespace string
+	err       error
+}
+
+// kubeClientset returns the clientset of the kubeconfig, KUBECONFIG or the in-cluster config, and its namespace. It
+// is built once since the daemon reads the same objects on every validation
+func kubeClientset() (*kubernetes.Clientset, string, error) {
+	kubeClient.once.Do(func() {
+		config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+		restConfig, err := config.ClientConfig()
+		if err != nil {
+			kubeClient.err = fmt.Errorf("error: failed to load the kubeconfig: %w", err)
+			return
+		}
+		if kubeClient.namespace, _, err = config.Namespace(); err != nil {
+			kubeClient.err = err
+			return
+		}
+		kubeClient.clientset, kubeClient.err = kubernetes.NewForConfig(restConfig)
+	})
+	return kubeClient.clientset, kubeClient.namespace, kubeClient.err
+}
+
+// readConfigMap returns the key of the ConfigMap, from its data or its binaryData
+func (o kubeObject) readConfigMap(ctx context.Context) ([]byte, error) {
+	clientset, namespace, err := kubeClientset()
+	if err != nil {
+		return nil, err
+	}
+	if o.namespace != "" {
+		namespace = o.namespace
+	}
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, o.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read [%s]: %w", o.uri, err)
+	}
+	if data, ok := cm.Data[o.key]; ok {
+		return []byte(data), nil
+	}
+	if data, ok := cm.BinaryData[o.key]; ok {
+		return data, nil
+	}
+	return nil, fmt.Errorf("error: ConfigMap [%s/%s] has no key [%s]", namespace, o.name, o.key)
+}
+
+// readSecret returns the key of the Secret
+func (o kubeObject) readSecret(ctx context.Context) (string, error) {
+	clientset, namespace, err := kubeClientset()
+	if err != nil {
+		return "", err
+	}
+	if o.namespace != "" {
+		namespace = o.namespace
+	}
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, o.name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error: failed to read [%s]: %w", o.uri, err)
+	}
+	data, ok := secret.Data[o.key]
+	if !ok {
+		return "", fmt.Errorf("error: Secret [%s/%s] has no key [%s]", namespace, o.name, o.key)
+	}
+	return string(data), nil
+}
+
+// readConfigMapMaintainersFile reads a maintainers file from a ConfigMap, its maintainers.yaml key if the URL has no
+// key
+func readConfigMapMaintainersFile(ctx context.Context, uri string) (Maintainers, error) {
+	o, err := parseKubeObject(uri, "maintainers.yaml")
+	if err != nil {
+		return nil, err
+	}
+	data, err := o.readConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := maintainers.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to decode maintainers file [%s]: %w", uri, err)
+	}
+	return ms, nil
+}
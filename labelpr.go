@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// chartPathPrefixes are the repository directories whose first path element is a chart name
+var chartPathPrefixes = []string{"charts/", "packages/"}
+
+func runLabelPR(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		repo                string
+		number              int
+		apply               bool
+		pf                  providerFlags
+	)
+	fs := flag.NewFlagSet("label-pr", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&repo, "repo", "", "owner/name of the repository the pull request belongs to")
+	fs.IntVar(&number, "pr", 0, "number of the pull request to label")
+	fs.BoolVar(&apply, "apply", false, "execute the plan instead of only printing it")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	pf.register(fs)
+	fs.Parse(args)
+	if repo == "" || number == 0 {
+		return errors.New("error: both --repo and --pr are required")
+	}
+	// Pull requests change with every push, so always revalidate instead of trusting the cache TTL
+	pf.cacheTTL = 0
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	client, err := pf.provider(ctx)
+	if err != nil {
+		return err
+	}
+	p, err := planPullRequestLabels(ctx, client, maintainers, repo, number)
+	if err != nil {
+		return err
+	}
+	p.print(os.Stdout)
+	if p.empty() {
+		return nil
+	}
+	if !apply {
+		fmt.Println("\nNothing was changed, run again with --apply to execute this plan")
+		return nil
+	}
+	fmt.Println()
+	return p.apply(os.Stdout)
+}
+
+// planPullRequestLabels maps the files changed by a pull request to the charts they belong to and plans adding
+// the GitHub labels of every touched chart
+func planPullRequestLabels(ctx context.Context, client provider, maintainers Maintainers, repo string, number int) (*plan, error) {
+	files, err := client.listPullRequestFiles(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := client.getPullRequest(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var labels []string
+	seen := make(map[string]struct{})
+	for _, chartName := range changedCharts(files) {
+		m, chart := maintainers.FindChart(chartName)
+		if chart == nil {
+			fmt.Printf("warning: chart [%s] changed in pull request #%d is not in the maintainers file\n", chartName, number)
+			continue
+		}
+		fmt.Printf("chart [%s] is maintained by [%s]\n", chart.Name, m.Name)
+		for _, label := range chart.GithubLabels {
+			if _, ok := seen[label]; !ok {
+				seen[label] = struct{}{}
+				labels = append(labels, label)
+			}
+		}
+	}
+	fmt.Println()
+
+	p := &plan{}
+	missing := missingIssueLabels(*pr, labels)
+	if len(missing) == 0 {
+		p.add(planSkip, "pull request", fmt.Sprintf("#%d", number), nil)
+		return p, nil
+	}
+	p.add(planUpdate, "pull request", fmt.Sprintf("#%d add labels %v", number, missing), func() error {
+		return client.addPullRequestLabels(ctx, repo, number, missing)
+	})
+	return p, nil
+}
+
+// changedCharts returns the sorted names of the charts the given repository paths belong to
+func changedCharts(files []string) []string {
+	charts := make(map[string]struct{})
+	for _, file := range files {
+		for _, prefix := range chartPathPrefixes {
+			rest := strings.TrimPrefix(file, prefix)
+			if rest == file {
+				continue
+			}
+			if i := strings.Index(rest, "/"); i > 0 {
+				charts[rest[:i]] = struct{}{}
+			}
+		}
+	}
+	names := make([]string, 0, len(charts))
+	for name := range charts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
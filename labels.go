@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func runLabels(ctx context.Context, args []string) error {
+	if len(args) > 0 && args[0] == "list" {
+		return runLabelsList(ctx, args[1:])
+	}
+	usage := errors.New("error: usage: cowhand labels list | add|remove <label> [--team <name>] [--chart-glob <pattern>]")
+	if len(args) < 2 || args[0] != "add" && args[0] != "remove" || strings.HasPrefix(args[1], "-") {
+		return usage
+	}
+	action, label := args[0], strings.TrimSpace(args[1])
+	var (
+		maintainersFilePath string
+		team                string
+		chartGlob           string
+	)
+	fs := flag.NewFlagSet("labels "+action, flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&team, "team", "", "only change the charts of this team, or of the team a unique part of its name matches such as team/area1")
+	fs.StringVar(&chartGlob, "chart-glob", "", "only change the charts whose name matches this pattern, e.g. rancher-monitoring*")
+	fs.Parse(args[2:])
+	if label == "" || team == "" && chartGlob == "" {
+		return usage
+	}
+	if _, err := path.Match(chartGlob, ""); err != nil {
+		return fmt.Errorf("error: invalid --chart-glob [%s]: %w", chartGlob, err)
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	teamName := ""
+	if team != "" {
+		m, err := maintainers.FindTeam(team)
+		if err != nil {
+			return fmt.Errorf("error: %w", err)
+		}
+		teamName = m.Name
+	}
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	var changed []string
+	for _, t := range teamNodes(doc) {
+		if teamName != "" && nodeName(t) != teamName {
+			continue
+		}
+		charts := mappingValue(t, "charts")
+		if charts == nil || charts.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, chart := range charts.Content {
+			name := nodeName(chart)
+			if ok, _ := path.Match(chartGlob, name); chartGlob != "" && !ok {
+				continue
+			}
+			var done bool
+			if action == "add" {
+				done = addLabelNode(chart, label)
+			} else {
+				done = removeLabelNode(chart, label)
+			}
+			if done {
+				changed = append(changed, name)
+			}
+		}
+	}
+	if len(changed) == 0 {
+		fmt.Println("No chart to change")
+		return nil
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	verb := "added label [%s] to %s"
+	if action == "remove" {
+		verb = "removed label [%s] from %s"
+	}
+	fmt.Printf("\n"+verb+"\n", label, pluralize(len(changed), "chart"))
+	return nil
+}
+
+func runLabelsList(ctx context.Context, args []string) error {
+	var maintainersFilePath string
+	fs := flag.NewFlagSet("labels list", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.Parse(args)
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	usages := labelUsages(maintainers)
+	if len(usages) == 0 {
+		fmt.Println("No labels")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LABEL\tCHARTS\tTEAMS")
+	for _, u := range usages {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", u.label, u.charts, strings.Join(u.teams, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for i, pair := range similarLabels(usages) {
+		if i == 0 {
+			fmt.Println()
+		}
+		fmt.Printf("warning: labels [%s] and [%s] look alike, one of them may be a typo\n", pair[0], pair[1])
+	}
+	return nil
+}
+
+// labelUsage is how many charts carry a label and the teams of those charts
+type labelUsage struct {
+	label  string
+	charts int
+	teams  []string
+}
+
+// labelUsages returns the usage of every label of the maintainers file sorted by label
+func labelUsages(maintainers Maintainers) []*labelUsage {
+	byLabel := make(map[string]*labelUsage)
+	var usages []*labelUsage
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			for _, label := range chart.GithubLabels {
+				u, ok := byLabel[label]
+				if !ok {
+					u = &labelUsage{label: label}
+					byLabel[label] = u
+					usages = append(usages, u)
+				}
+				u.charts++
+				if !containsString(u.teams, m.Name) {
+					u.teams = append(u.teams, m.Name)
+				}
+			}
+		}
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].label < usages[j].label })
+	return usages
+}
+
+// similarLabels returns the pairs of labels that only differ by case or punctuation, e.g. area/monitoring and
+// area-monitoring. Labels a letter apart are not reported as team/area1 and team/area2 are both meant
+func similarLabels(usages []*labelUsage) [][2]string {
+	var pairs [][2]string
+	for i := range usages {
+		for j := i + 1; j < len(usages); j++ {
+			if labelKey(usages[i].label) == labelKey(usages[j].label) {
+				pairs = append(pairs, [2]string{usages[i].label, usages[j].label})
+			}
+		}
+	}
+	return pairs
+}
+
+func labelKey(label string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, label)
+}
+
+// addLabelNode appends label to the githubLabels of a chart node and reports whether it was missing
+func addLabelNode(chart *yaml.Node, label string) bool {
+	labels := mappingValue(chart, "githubLabels")
+	if labels == nil || labels.Kind != yaml.SequenceNode {
+		labels = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		setMappingValue(chart, "githubLabels", labels)
+	}
+	for _, l := range labels.Content {
+		if l.Value == label {
+			return false
+		}
+	}
+	labels.Content = append(labels.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: label})
+	// A label added to githubLabels: [] goes on its own line, other flow lists stay flow lists
+	if len(labels.Content) == 1 {
+		labels.Style = 0
+	}
+	return true
+}
+
+// removeLabelNode removes label from the githubLabels of a chart node and reports whether it had it
+func removeLabelNode(chart *yaml.Node, label string) bool {
+	labels := mappingValue(chart, "githubLabels")
+	if labels == nil || labels.Kind != yaml.SequenceNode {
+		return false
+	}
+	kept := labels.Content[:0]
+	for _, l := range labels.Content {
+		if l.Value != label {
+			kept = append(kept, l)
+		}
+	}
+	removed := len(kept) != len(labels.Content)
+	labels.Content = kept
+	formatSequenceStyle(labels)
+	return removed
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// configureLogging installs the slog handler selected by COWHAND_LOG_FORMAT, text or json, and COWHAND_LOG_LEVEL,
+// debug, info, warn or error, as the default logger the diagnostics of cowhand and of the validation rules go to.
+// Without either the default logger of the log package is kept
+func configureLogging(w io.Writer) error {
+	format, level := os.Getenv("COWHAND_LOG_FORMAT"), os.Getenv("COWHAND_LOG_LEVEL")
+	if format == "" && level == "" {
+		return nil
+	}
+	handler, err := newLogHandler(w, format, level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// newLogHandler returns the handler of the format writing the records of level and above to w, info by default
+func newLogHandler(w io.Writer, format, level string) (slog.Handler, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("error: unknown log level [%s], use debug, info, warn or error", level)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.NewTextHandler(w, opts), nil
+	case "json":
+		return slog.NewJSONHandler(w, opts), nil
+	}
+	return nil, fmt.Errorf("error: unknown log format [%s], use text or json", format)
+}
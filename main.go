@@ -1,130 +1,444 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
 	yaml "gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	sigyaml "sigs.k8s.io/yaml"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+	"github.com/pennyscissors/go-playground/pkg/validate"
 )
 
-type Maintainers []*Maintainer
-type Maintainer struct {
-	Name    string  `yaml:"name"`
-	Contact Contact `yaml:"contact"`
-	Charts  []Chart `yaml:"charts"`
-}
+// The model of the maintainers file is imported by other tools from pkg/maintainers, the aliases keep the commands
+// written against it short
+type (
+	Maintainers = maintainers.Maintainers
+	Maintainer  = maintainers.Maintainer
+	Contact     = maintainers.Contact
+	Chart       = maintainers.Chart
+)
 
-type Contact struct {
-	Email        string `yaml:"email"`
-	SlackChannel string `yaml:"slackChannel,omitempty"`
-	URL          string `yaml:"url,omitempty"`
+func main() {
+	name, args := "validate", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Printf("error: unknown command [%s]\n\n", name)
+		printUsage()
+		os.Exit(2)
+	}
+	if err := configureLogging(os.Stderr); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	// Interrupting cowhand cancels the requests it is waiting on
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	shutdownTracing, err := setupTracing(ctx)
+	if err != nil {
+		slog.Warn("tracing disabled", "error", err)
+	}
+	ctx, span := startSpan(ctx, "cowhand "+name)
+	err = cmd.run(ctx, args)
+	var code exitCode
+	if errors.As(err, &code) {
+		endSpan(span, nil)
+	} else {
+		endSpan(span, err)
+	}
+	// Spans are flushed even once interrupted, the command context is done by then
+	shutdownTracing(context.Background())
+	stop()
+	if err != nil {
+		if errors.As(err, &code) {
+			os.Exit(int(code))
+		}
+		fmt.Println(err)
+		os.Exit(1)
+	}
 }
 
-type Chart struct {
-	Name          string   `yaml:"name"`
-	GenerateIssue bool     `yaml:"generateIssue"`
-	GithubLabels  []string `yaml:"githubLabels"`
-}
+func runValidate(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		repoURL             string
+		githubRepo          string
+		configFilePath      string
+		helmRepo            string
+		generateDir         string
+		mirrorIndexPath     string
+		branches            string
+		gitRemote           string
+		branchIndexPath     string
+		branchMaintainers   bool
+		fix                 bool
+		pr                  pullRequestFlags
+		assetsDir           string
+		packagesDir         string
+		plugins             string
+		schemaValidate      bool
+		tn                  teamNotifyFlags
+		workers             int
+		pf                  providerFlags
+	)
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file, or its http(s), s3://, gs://, git+ or configmap:// URL")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.StringVar(&repoURL, "repo-url", "", "URL of a chart repository to validate against its index.yaml, or an oci:// registry namespace, overrides --index-file")
+	fs.StringVar(&helmRepo, "from-helm-cache", "", "name of a repository added with helm repo add to validate against its cached index, overrides --index-file")
+	fs.StringVar(&generateDir, "generate-index", "", "directory of chart archives or unpacked charts to build the index from instead of reading one, overrides --index-file")
+	fs.StringVar(&mirrorIndexPath, "mirror-index", "", "if set, also report the charts and versions that differ between the index and this mirror of it, e.g. an air-gapped copy")
+	fs.StringVar(&branches, "branches", "", "comma separated branches of --git-remote to compare chart ownership across instead, e.g. dev-v2.9,dev-v2.10")
+	fs.StringVar(&gitRemote, "git-remote", "origin", "git remote the --branches are fetched from")
+	fs.StringVar(&branchIndexPath, "branch-index-path", "index.yaml", "path of the index file inside each branch")
+	fs.BoolVar(&branchMaintainers, "branch-maintainers", false, "use the maintainers file of each branch instead of --maintainers-file")
+	fs.BoolVar(&fix, "fix", false, "repair whitespace, duplicate labels and charts and generateIssue on crd charts in the maintainers file before validating it")
+	fs.StringVar(&assetsDir, "assets-dir", "", "if set, also validate the Chart.yaml packaged in the chart archives under this directory, e.g. ./assets")
+	fs.StringVar(&packagesDir, "packages-dir", "", "if set, also validate the dependencies of the charts under this directory, e.g. ./packages")
+	fs.IntVar(&workers, "parallel", defaultParallelism, "how many repositories of the config file or --branches are loaded and validated at once")
+	fs.BoolVar(&schemaValidate, "schema-validate", false, "also validate the maintainers file against its JSON Schema, reporting the path of every violation")
+	fs.StringVar(&plugins, "plugin", "", "comma separated executables run as extra rules, each receiving the maintainers and index as JSON on stdin and printing a JSON array of results on stdout")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file defining the chart repositories to validate")
+	fs.BoolVar(&tn.notify, "notify-teams", false, "post the findings about the charts of every team to its teamNotifications in the config file, or else its Slack channel")
+	fs.BoolVar(&tn.email, "email-teams", false, "email every team a digest of the findings about its charts, through the SMTP server of the email of the config file")
+	fs.BoolVar(&tn.page, "page-critical", false, "trigger a PagerDuty event for every critical chart of the config file that no team maintains or whose team has no contact, and resolve the others")
+	fs.StringVar(&githubRepo, "github-repo", "", "if set, validate that every label exists in this owner/name repository of the selected provider")
+	pf.register(fs)
+	pr.register(fs)
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	// An index given on the command line is validated on its own, ignoring the repositories of the config file
+	explicitIndex := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "index-file" || f.Name == "repo-url" || f.Name == "from-helm-cache" || f.Name == "generate-index" {
+			explicitIndex = true
+		}
+	})
+	if repoURL != "" {
+		indexFilePath = repoURL
+		// OCI registries have no index file, the charts are listed from the registry instead
+		if !isOCI(repoURL) {
+			indexFilePath = strings.TrimSuffix(repoURL, "/") + "/index.yaml"
+		}
+	}
+	if helmRepo != "" {
+		if indexFilePath, err = helmCacheIndexFile(helmRepo); err != nil {
+			return err
+		}
+	}
+	if generateDir != "" {
+		indexFilePath = generateDir
+	}
 
-type IndexFile struct {
-	Entries map[string]interface{} `yaml:"entries"`
-}
+	if fix {
+		if err := fixMaintainersFile(ctx, maintainersFilePath, &pr); err != nil {
+			return err
+		}
+	}
 
-func main() {
-	maintainersFilePath := "./maintainers.yaml"
-	indexFilePath := "./charts/index.yaml"
-	if err := validateMaintainersFile(maintainersFilePath, indexFilePath); err != nil {
+	if schemaValidate {
+		problems, err := validateMaintainersSchema(maintainersFilePath)
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+	}
+
+	if branches != "" {
+		return validateBranches(ctx, gitRemote, strings.Split(branches, ","), branchIndexPath, maintainersFilePath, branchMaintainers, workers)
+	}
+	var pluginPaths []string
+	if plugins != "" {
+		pluginPaths = strings.Split(plugins, ",")
+	}
+	if len(config.Repositories) > 0 && !explicitIndex {
+		if tn.notify || tn.email || tn.page {
+			return errors.New("error: --notify-teams, --email-teams and --page-critical only support a single index, not the repositories of the config file")
+		}
+		if err := validateRepositoriesFile(ctx, config, maintainersFilePath, configFilePath, pluginPaths, workers); err != nil {
+			fmt.Println(err)
+		}
+	} else if err := validateMaintainersFile(ctx, config, maintainersFilePath, indexFilePath, mirrorIndexPath, pluginPaths, tn); err != nil {
 		fmt.Println(err)
 	}
+	if assetsDir != "" || packagesDir != "" {
+		if err := validateChartFiles(ctx, maintainersFilePath, assetsDir, packagesDir); err != nil {
+			return err
+		}
+	}
+	if githubRepo != "" {
+		client, err := pf.provider(ctx)
+		if err != nil {
+			return err
+		}
+		if err := validateGitHubLabels(ctx, maintainersFilePath, githubRepo, client); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func validateMaintainersFile(maintainersFilePath, indexFilePath string) error {
-	maintainers, err := decodeMaintainersFile(maintainersFilePath)
+// validateMaintainersFile validates the maintainers file against the index, also running the plugins, and compares
+// the index to its mirror if mirrorIndexPath is set. The findings are then sent to the teams as tn tells
+func validateMaintainersFile(ctx context.Context, config *Config, maintainersFilePath, indexFilePath, mirrorIndexPath string, plugins []string, tn teamNotifyFlags) error {
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
 	if err != nil {
-		fmt.Println(err)
+		return err
+	}
+	index, err := decodeIndexFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	results, err := validateMaintainersResults(ctx, config, maintainers, index, maintainersFilePath, indexFilePath)
+	if err != nil {
+		return err
+	}
+	results = append(results, validate.RunPlugins(ctx, plugins, maintainers, index, maintainersFilePath, indexFilePath)...)
+	problems := results.Strings()
+	if mirrorIndexPath != "" {
+		mirror, err := decodeIndexFile(ctx, mirrorIndexPath)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, compareMirror(index, mirror, indexFilePath, mirrorIndexPath)...)
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	messages := teamFindings(config, maintainers, results, maintainersFilePath)
+	var errs []error
+	if tn.notify {
+		errs = append(errs, notifyTeams(ctx, config, messages))
+	}
+	if tn.email {
+		errs = append(errs, emailTeams(ctx, config, messages))
+	}
+	if tn.page {
+		errs = append(errs, pageCriticalCharts(ctx, config, maintainers, index, maintainersFilePath))
+	}
+	return errors.Join(errs...)
+}
+
+// validateRepositoriesFile validates the charts of every repository in the config against its own index
+func validateRepositoriesFile(ctx context.Context, config *Config, maintainersFilePath, configFilePath string, plugins []string, workers int) error {
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	for _, problem := range validateRepositories(ctx, config, maintainers, maintainersFilePath, configFilePath, plugins, workers) {
+		fmt.Println(problem)
 	}
-	// Build map of charts from maintainers file and validate it there are no chart or label duplicates
-	maintainersCharts := make(map[string]struct{})
-	duplicateCharts := make(map[string]struct{})
+	return nil
+}
+
+// validateMaintainersResults returns the problems found in the maintainers file and its cross-check against the index,
+// the file paths are only used to build the messages
+func validateMaintainersResults(ctx context.Context, config *Config, maintainers Maintainers, index *repo.IndexFile, maintainersFilePath, indexFilePath string) (validate.Results, error) {
+	return validate.Run(ctx, maintainers, index, validate.WithAliases(config.Aliases), validate.WithSuggestions(config.Suggestions), validate.WithSources(maintainersFilePath, indexFilePath), validate.WithLogHandler(slog.Default().Handler()))
+}
+
+// validateRepositories is validateMaintainersResults for a config with several repositories, each repository is
+// cross-checked and run through the plugins with the charts that belong to it, and an index that fails to load does
+// not stop the others. Up to workers repositories are validated at once
+func validateRepositories(ctx context.Context, config *Config, maintainers Maintainers, maintainersFilePath, configFilePath string, plugins []string, workers int) []string {
+	defaultRepository := config.defaultRepository()
+	problems := validate.Lint(maintainers, defaultRepository).Strings()
 	for _, m := range maintainers {
 		for _, chart := range m.Charts {
-			// Validate crd charts do not have generateIssue == true since we don't track crd charts on issues separately
-			if strings.HasSuffix(chart.Name, "-crd") && chart.GenerateIssue {
-				fmt.Printf("error: crd chart [%s] has field [generateIssue: %t] which is incorrect as crd charts are not tracked in issues separately \n", chart.Name, chart.GenerateIssue)
-			}
-			// Validate each chart does not have any GitHub label duplicates
-			duplicateLabels := make(map[string]struct{})
-			for _, label := range chart.GithubLabels {
-				if _, ok := duplicateLabels[label]; ok {
-					fmt.Printf("error: chart [%s] has duplicate label [%s]\n", chart.Name, label)
+			for _, name := range chart.Repositories {
+				if config.repository(name) == nil {
+					problems = append(problems, fmt.Sprintf("error: chart [%s] belongs to repository [%s] which is not defined in config file [%s]", chart.Name, name, configFilePath))
 				}
-				duplicateLabels[label] = struct{}{}
 			}
-			// Validate maintainers do not have any chart duplicates in their team or accross teams
-			if _, ok := maintainersCharts[chart.Name]; ok {
-				if _, ok := duplicateCharts[chart.Name]; !ok {
-					fmt.Printf("error: chart [%s] is a duplicate or wrongly set as maintained by more than one team\n", chart.Name)
-					duplicateCharts[chart.Name] = struct{}{}
-				}
+		}
+	}
+	// The repositories are loaded and validated concurrently, their problems are kept in the order of the config
+	perRepository := make([][]string, len(config.Repositories))
+	parallel(len(config.Repositories), workers, func(i int) {
+		r := config.Repositories[i]
+		index, err := decodeIndexFile(ctx, r.Index)
+		if err != nil {
+			perRepository[i] = []string{fmt.Sprintf("error: failed to load the index of repository [%s]: %v", r.Name, err)}
+			return
+		}
+		inRepository := maintainers.InRepository(r.Name, defaultRepository)
+		found := validate.CrossCheckIndex(config.Aliases, config.Suggestions, inRepository, index, r.Name, maintainersFilePath, r.Index).Strings()
+		found = append(found, validate.RunPlugins(ctx, plugins, inRepository, index, maintainersFilePath, r.Index).Strings()...)
+		if r.Mirror != "" {
+			mirror, err := decodeIndexFile(ctx, r.Mirror)
+			if err != nil {
+				found = append(found, fmt.Sprintf("error: failed to load the mirror index of repository [%s]: %v", r.Name, err))
+			} else {
+				found = append(found, compareMirror(index, mirror, r.Index, r.Mirror)...)
 			}
-			maintainersCharts[chart.Name] = struct{}{}
 		}
+		perRepository[i] = found
+	})
+	for _, found := range perRepository {
+		problems = append(problems, found...)
 	}
-	index, err := decodeIndexFile(indexFilePath)
+	return problems
+}
+
+// validateGitHubLabels checks that every label referenced in the maintainers file exists in the repository
+func validateGitHubLabels(ctx context.Context, maintainersFilePath, repo string, client provider) error {
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
 	if err != nil {
-		fmt.Println(err)
-	}
-	if len(index.Entries) == 0 {
-		fmt.Println("error: index file [%s] has no chart entries", indexFilePath)
+		return err
 	}
-	// Validate all charts in the index file exist in the maintainers file
-	for chartName := range index.Entries {
-		if _, ok := maintainersCharts[chartName]; !ok {
-			fmt.Printf("error: chart [%s] is missing from maintainers file [%s]\n", chartName, maintainersFilePath)
+	var names []string
+	seen := make(map[string]struct{})
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			for _, label := range chart.GithubLabels {
+				if _, ok := seen[label]; !ok {
+					seen[label] = struct{}{}
+					names = append(names, label)
+				}
+			}
 		}
 	}
-	// Validate all charts in the maintainers file exist in the index file
-	for chartName := range maintainersCharts {
-		if _, ok := index.Entries[chartName]; !ok {
-			fmt.Printf("error: chart [%s] does not exist in index file [%s]\n", chartName, indexFilePath)
+	exists, err := labelsExistIn(ctx, client, repo, names)
+	if err != nil {
+		return err
+	}
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			for _, label := range chart.GithubLabels {
+				if !exists[label] {
+					fmt.Printf("error: chart [%s] has label [%s] which does not exist in repository [%s]\n", chart.Name, label, repo)
+				}
+			}
 		}
-		delete(index.Entries, chartName)
 	}
 	return nil
 }
 
-func decodeMaintainersFile(path string) (Maintainers, error) {
-	var maintainers Maintainers
-	file, err := os.Open(path)
-	if err != nil {
+// decodeMaintainersFile is maintainers.DecodeFile for the commands, which name their teams maintainers, returning
+// early once ctx is done. An http(s) path is downloaded, see fetchMaintainersFile, an s3:// or gs:// one is read
+// from its bucket, a git+ one from its git repository, see parseGitFile, and a configmap:// one from the key of its
+// ConfigMap, see parseKubeObject
+func decodeMaintainersFile(ctx context.Context, path string) (ms Maintainers, err error) {
+	_, span := startSpan(ctx, "load maintainers file", attribute.String("cowhand.maintainers_file", path))
+	defer func() { endSpan(span, err) }()
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	if err := decodeYAMLFile(file, &maintainers); err != nil {
-		return nil, err
+	switch {
+	case isRemote(path):
+		return fetchMaintainersFile(ctx, path)
+	case isObject(path):
+		return readObjectMaintainersFile(ctx, path)
+	case isGit(path):
+		return readGitMaintainersFile(ctx, path)
+	case strings.HasPrefix(path, configMapScheme):
+		return readConfigMapMaintainersFile(ctx, path)
+	}
+	if ms, err = maintainers.DecodeFile(path); err != nil {
+		return nil, fmt.Errorf("error: %w", err)
 	}
-	return maintainers, nil
+	return ms, nil
+}
+
+// isRemoteMaintainersFile reports whether the maintainers file is not a local file, which the edit commands refuse
+func isRemoteMaintainersFile(path string) bool {
+	return isRemote(path) || isObject(path) || isGit(path) || strings.HasPrefix(path, configMapScheme)
 }
 
-func decodeIndexFile(path string) (*IndexFile, error) {
-	var index IndexFile
-	file, err := os.Open(path)
+// decodeIndexFile loads the index from the source selected by the scheme of path, see newIndexSource
+func decodeIndexFile(ctx context.Context, path string) (index *repo.IndexFile, err error) {
+	ctx, span := startSpan(ctx, "load index", attribute.String("cowhand.index", path))
+	defer func() { endSpan(span, err) }()
+	source, err := newIndexSource(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	if err := decodeYAMLFile(file, &index); err != nil {
-		return nil, err
+	return source.Load(ctx)
+}
+
+// decodeIndex loads an index the same way helm's repo.LoadIndexFile does, which only accepts a path
+func decodeIndex(data []byte, source string) (*repo.IndexFile, error) {
+	if len(data) == 0 {
+		return nil, repo.ErrEmptyIndexYaml
+	}
+	index := &repo.IndexFile{}
+	var err error
+	if json.Valid(data) {
+		err = json.Unmarshal(data, index)
+	} else {
+		err = sigyaml.UnmarshalStrict(data, index)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", source, err)
+	}
+	if index.APIVersion == "" {
+		return nil, fmt.Errorf("error loading %s: %w", source, repo.ErrNoAPIVersion)
 	}
-	return &index, nil
+	dropInvalidEntries(index, source)
+	index.SortEntries()
+	return index, nil
+}
+
+// dropInvalidEntries removes the nil and invalid chart versions of index, like helm does when it loads an index
+func dropInvalidEntries(index *repo.IndexFile, source string) {
+	for name, versions := range index.Entries {
+		valid := versions[:0]
+		for _, cv := range versions {
+			if cv == nil {
+				continue
+			}
+			if cv.Metadata == nil {
+				cv.Metadata = &chart.Metadata{}
+			}
+			if cv.APIVersion == "" {
+				cv.APIVersion = chart.APIVersionV1
+			}
+			if err := cv.Validate(); err != nil && !strings.HasPrefix(err.Error(), "validation: more than one dependency with name or alias") {
+				slog.Warn("skipping loading invalid entry", "chart", name, "version", cv.Version, "source", source, "error", err)
+				continue
+			}
+			valid = append(valid, cv)
+		}
+		index.Entries[name] = valid
+	}
+}
+
+// upstreamURL returns the chart's home page, falling back to its first source
+func upstreamURL(cv *repo.ChartVersion) string {
+	if cv.Home != "" {
+		return cv.Home
+	}
+	if len(cv.Sources) > 0 {
+		return cv.Sources[0]
+	}
+	return ""
 }
 
 func decodeYAMLFile(r io.Reader, target interface{}) error {
-	data, err := io.ReadAll(r)
-	if err != nil {
+	// An empty file decodes to nothing, like yaml.Unmarshal of no data
+	if err := yaml.NewDecoder(r).Decode(target); err != nil && !errors.Is(err, io.EOF) {
 		return err
 	}
-	return yaml.Unmarshal(data, target)
+	return nil
 }
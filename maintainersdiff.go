@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+func runDiff(ctx context.Context, args []string) error {
+	var files []string
+	// Files may come before the flags, like merge
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		files, args = append(files, args[0]), args[1:]
+	}
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Parse(args)
+	files = append(files, fs.Args()...)
+	if len(files) != 2 {
+		return errors.New("error: usage: cowhand diff <old file> <new file>")
+	}
+
+	old, err := decodeMaintainersFile(ctx, files[0])
+	if err != nil {
+		return err
+	}
+	updated, err := decodeMaintainersFile(ctx, files[1])
+	if err != nil {
+		return err
+	}
+	changes := ownershipChanges(old, updated)
+	if len(changes) == 0 {
+		fmt.Println("No ownership changes")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	return nil
+}
+
+// ownershipChanges describes how the ownership changed from old to updated: the teams added and removed, the
+// contacts changed, the charts added, removed or moved between teams and the charts whose fields changed. Charts
+// are matched by name and teams by exact name, so a renamed team shows as removed and added
+func ownershipChanges(old, updated Maintainers) []string {
+	var changes []string
+	for _, m := range old {
+		if team, _ := updated.FindTeamByName(m.Name); team == nil {
+			changes = append(changes, fmt.Sprintf("team [%s] removed", m.Name))
+		}
+	}
+	for _, m := range updated {
+		team, _ := old.FindTeamByName(m.Name)
+		if team == nil {
+			changes = append(changes, fmt.Sprintf("team [%s] added", m.Name))
+			continue
+		}
+		changes = append(changes, contactChanges(m.Name, team.Contact, m.Contact)...)
+	}
+
+	for _, m := range old {
+		for _, chart := range m.Charts {
+			if owner, _ := updated.FindChart(chart.Name); owner == nil {
+				changes = append(changes, fmt.Sprintf("chart [%s] removed from [%s]", chart.Name, m.Name))
+			}
+		}
+	}
+	for _, m := range updated {
+		for i := range m.Charts {
+			chart := &m.Charts[i]
+			// A chart split between teams by maintainedVersions is compared with its entry in the same team first
+			if oldChart := old.FindTeamChart(m.Name, chart.Name); oldChart != nil {
+				changes = append(changes, chartChanges(m.Name, oldChart, chart)...)
+				continue
+			}
+			owner, oldChart := old.FindChart(chart.Name)
+			if owner == nil {
+				changes = append(changes, fmt.Sprintf("chart [%s] added to [%s]", chart.Name, m.Name))
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("chart [%s] moved from [%s] to [%s]", chart.Name, owner.Name, m.Name))
+			changes = append(changes, chartChanges(m.Name, oldChart, chart)...)
+		}
+	}
+	return changes
+}
+
+func contactChanges(team string, old, updated Contact) []string {
+	var changes []string
+	for _, field := range []struct{ name, old, updated string }{
+		{"email", old.Email, updated.Email},
+		{"slackChannel", old.SlackChannel, updated.SlackChannel},
+		{"url", old.URL, updated.URL},
+	} {
+		if field.old != field.updated {
+			changes = append(changes, fmt.Sprintf("team [%s] %s changed from [%s] to [%s]", team, field.name, field.old, field.updated))
+		}
+	}
+	return changes
+}
+
+func chartChanges(team string, old, updated *Chart) []string {
+	var changes []string
+	if old.GenerateIssue != updated.GenerateIssue {
+		changes = append(changes, fmt.Sprintf("chart [%s] of [%s] generateIssue changed from [%t] to [%t]", updated.Name, team, old.GenerateIssue, updated.GenerateIssue))
+	}
+	if strings.Join(old.GithubLabels, "\n") != strings.Join(updated.GithubLabels, "\n") {
+		changes = append(changes, fmt.Sprintf("chart [%s] of [%s] githubLabels changed from [%s] to [%s]", updated.Name, team, strings.Join(old.GithubLabels, ", "), strings.Join(updated.GithubLabels, ", ")))
+	}
+	if old.MaintainedVersions != updated.MaintainedVersions {
+		changes = append(changes, fmt.Sprintf("chart [%s] of [%s] maintainedVersions changed from [%s] to [%s]", updated.Name, team, old.MaintainedVersions, updated.MaintainedVersions))
+	}
+	return changes
+}
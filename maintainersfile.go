@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// decodeMaintainersNode decodes the maintainers file into a yaml.Node so it can be edited and written back with
+// its comments, returning the original contents alongside it for diffing
+func decodeMaintainersNode(path string) (*yaml.Node, []byte, error) {
+	if isRemoteMaintainersFile(path) {
+		return nil, nil, fmt.Errorf("error: [%s] is a remote maintainers file, edit a local copy instead", path)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil, nil, fmt.Errorf("error: [%s] is a directory of team files, pass the file of the team to edit with --maintainers-file", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("error: failed to decode maintainers file [%s]: %w", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, nil, fmt.Errorf("error: maintainers file [%s] is not a list of teams", path)
+	}
+	return &doc, data, nil
+}
+
+// encodeMaintainersNode renders the maintainers file with a blank line between teams, like it is written by hand.
+// Comments, key order and quoting are kept by the nodes themselves, the blank lines yaml drops are restored from
+// original, the contents the file had when it was decoded, or nil for a new file
+func encodeMaintainersNode(doc *yaml.Node, original []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	lines := strings.SplitAfter(buf.String(), "\n")
+	teams := teamNodes(doc)
+	var out strings.Builder
+	for i, team := 0, 0; i < len(lines); i++ {
+		line := lines[i]
+		// Separate each team after the first, and the comments written above a team from whatever precedes them. The
+		// comments stay separated from the team too when they were in the file, which yaml keeps as a trailing newline.
+		// yaml already writes the blank line after the head comment of the document, which is not doubled
+		separated := i == 0 || lines[i-1] == "\n"
+		if !separated && !strings.HasPrefix(lines[i-1], "#") && startsTopLevelItem(lines, i) {
+			out.WriteString("\n")
+		} else if !separated && strings.HasPrefix(line, "- ") && team < len(teams) && strings.HasSuffix(teams[team].HeadComment, "\n") {
+			out.WriteString("\n")
+		}
+		if strings.HasPrefix(line, "- ") {
+			team++
+		}
+		out.WriteString(line)
+	}
+	if original == nil {
+		return []byte(out.String()), nil
+	}
+	return restoreBlankLines(original, []byte(out.String())), nil
+}
+
+// restoreBlankLines adds back the blank lines of original that encoded lost after a line found unchanged in both,
+// e.g. the blank lines grouping the charts of a team. Lines added right after such a blank line go before it, the
+// blank line is dropped along with the lines removed after it. A document start marker is kept as well
+func restoreBlankLines(original, encoded []byte) []byte {
+	var out strings.Builder
+	if bytes.HasPrefix(original, []byte("---\n")) && !bytes.HasPrefix(encoded, []byte("---\n")) {
+		out.WriteString("---\n")
+	}
+	ops := diffLines(splitLines(string(original)), splitLines(string(encoded)))
+	pending, lastBlank := false, true
+	for i, op := range ops {
+		switch {
+		case op.kind == '-' && op.text == "":
+			pending = pending || i > 0 && ops[i-1].kind == ' '
+			continue
+		case op.kind == '-':
+			pending = false
+			continue
+		case op.kind == ' ' && pending && !lastBlank && op.text != "":
+			out.WriteString("\n")
+		}
+		pending = pending && op.kind == '+'
+		lastBlank = op.text == ""
+		out.WriteString(op.text + "\n")
+	}
+	return []byte(out.String())
+}
+
+// startsTopLevelItem reports whether lines[i] starts a top level list item or the comments leading up to one
+func startsTopLevelItem(lines []string, i int) bool {
+	for ; i < len(lines); i++ {
+		switch {
+		case strings.HasPrefix(lines[i], "- "):
+			return true
+		case !strings.HasPrefix(lines[i], "#"):
+			return false
+		}
+	}
+	return false
+}
+
+// teamNodes returns the mapping node of every team in the maintainers file
+func teamNodes(doc *yaml.Node) []*yaml.Node {
+	return doc.Content[0].Content
+}
+
+// mappingValue returns the value of key in a mapping node, or nil if it is missing
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeChartNodes removes the charts for which remove returns true from every team, returning how many were removed
+func removeChartNodes(doc *yaml.Node, remove func(team, chart string) bool) int {
+	return len(extractChartNodes(doc, remove))
+}
+
+// extractChartNodes removes the charts for which remove returns true from every team and returns their nodes
+func extractChartNodes(doc *yaml.Node, remove func(team, chart string) bool) []*yaml.Node {
+	var removed []*yaml.Node
+	for _, team := range teamNodes(doc) {
+		name := mappingValue(team, "name")
+		charts := mappingValue(team, "charts")
+		if name == nil || charts == nil || charts.Kind != yaml.SequenceNode {
+			continue
+		}
+		kept := charts.Content[:0]
+		for _, chart := range charts.Content {
+			if chartName := mappingValue(chart, "name"); chartName != nil && remove(name.Value, chartName.Value) {
+				removed = append(removed, chart)
+				continue
+			}
+			kept = append(kept, chart)
+		}
+		charts.Content = kept
+	}
+	return removed
+}
+
+// insertChartNode adds the chart node to the charts of team, right after the chart it is the crd of or the other way
+// around if the team maintains it, and at the end of the list otherwise
+func insertChartNode(doc *yaml.Node, team, chartName string, value *yaml.Node) error {
+	for _, t := range teamNodes(doc) {
+		if name := mappingValue(t, "name"); name == nil || name.Value != team {
+			continue
+		}
+		charts := mappingValue(t, "charts")
+		if charts == nil || charts.Kind != yaml.SequenceNode {
+			charts = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			setMappingValue(t, "charts", charts)
+		}
+		// Block style, an empty list is written as charts: []
+		charts.Style = 0
+		position := len(charts.Content)
+		for i, c := range charts.Content {
+			name := mappingValue(c, "name")
+			if name == nil {
+				continue
+			}
+			if name.Value+"-crd" == chartName {
+				position = i + 1
+			} else if chartName+"-crd" == name.Value {
+				position = i
+			}
+		}
+		charts.Content = append(charts.Content[:position], append([]*yaml.Node{value}, charts.Content[position:]...)...)
+		return nil
+	}
+	return fmt.Errorf("error: team [%s] not found", team)
+}
+
+// validateMaintainersSchema returns the schema violations of the maintainers file, or of every team file if path is
+// a directory
+func validateMaintainersSchema(path string) ([]string, error) {
+	paths := []string{path}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		if paths, err = maintainers.TeamFiles(path); err != nil {
+			return nil, err
+		}
+	}
+	var problems []string
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		results, err := validate.Schema(data, p)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w", err)
+		}
+		problems = append(problems, results.Strings()...)
+	}
+	return problems, nil
+}
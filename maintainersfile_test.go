@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/cowhandtest"
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+func TestEncodeMaintainersNodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		// want is the encoded file, the file itself when empty
+		want string
+	}{
+		{
+			name: "head comment",
+			file: "# Maintainers of the charts\n\n- name: team-a\n  charts:\n    - name: foo\n\n- name: team-b\n  charts:\n    - name: bar\n",
+		},
+		{
+			name: "head comment and team comments",
+			file: "# Maintainers\n\n# team a\n\n- name: team-a\n  charts:\n    - name: foo\n\n# team b\n- name: team-b\n  charts:\n    - name: bar\n",
+		},
+		{
+			name: "charts grouped by blank lines",
+			file: "- name: team-a\n  contact:\n    email: a@example.com\n  charts:\n    - name: foo\n\n    - name: bar\n      githubLabels:\n        - \"team/a\"\n",
+		},
+		{
+			name: "document start marker",
+			file: "---\n# head\n- name: team-a\n- name: team-b\n",
+			want: "---\n# head\n- name: team-a\n\n- name: team-b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == "" {
+				want = tt.file
+			}
+			path := filepath.Join(t.TempDir(), "maintainers.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			// Every command editing the file encodes it again, the second rewrite must not change it any further
+			for i := 0; i < 2; i++ {
+				doc, original, err := decodeMaintainersNode(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := encodeMaintainersNode(doc, original)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Fatalf("rewrite %d:\ngot:\n%q\nwant:\n%q", i+1, got, want)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeMaintainersNodeCorpus(t *testing.T) {
+	fsys := cowhandtest.Fixtures()
+	for _, c := range cowhandtest.Cases(t) {
+		t.Run(c.Name, func(t *testing.T) {
+			original, err := fs.ReadFile(fsys, path.Join(c.Name, "maintainers.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc yaml.Node
+			if err := yaml.Unmarshal(original, &doc); err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := encodeMaintainersNode(&doc, original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The rewrite only changes the layout of the file, never the teams it holds
+			ms, err := maintainers.Decode(bytes.NewReader(encoded))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ms, c.Maintainers) {
+				t.Errorf("encoded file holds other teams:\n%s", encoded)
+			}
+			// Once the blank lines between teams are added, the next rewrite leaves the file as it is
+			var again yaml.Node
+			if err := yaml.Unmarshal(encoded, &again); err != nil {
+				t.Fatal(err)
+			}
+			reencoded, err := encodeMaintainersNode(&again, encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(reencoded, encoded) {
+				t.Errorf("second rewrite changed the file:\n%s", unifiedDiff("encoded", "reencoded", encoded, reencoded))
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func runMerge(ctx context.Context, args []string) error {
+	var output string
+	var files []string
+	// Files may come before the flags, e.g. cowhand merge a.yaml b.yaml -o merged.yaml
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		files, args = append(files, args[0]), args[1:]
+	}
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	fs.StringVar(&output, "o", "-", "path the merged maintainers file is written to, - for stdout")
+	fs.Parse(args)
+	files = append(files, fs.Args()...)
+	if len(files) < 2 {
+		return errors.New("error: usage: cowhand merge <file> <file>... [-o <merged file>]")
+	}
+
+	doc, original, err := decodeMaintainersNode(files[0])
+	if err != nil {
+		return err
+	}
+	merge, err := newMaintainersMerge(doc, files[0])
+	if err != nil {
+		return err
+	}
+	var conflicts []string
+	for _, file := range files[1:] {
+		other, _, err := decodeMaintainersNode(file)
+		if err != nil {
+			return err
+		}
+		fileConflicts, err := merge.add(other, file)
+		if err != nil {
+			return err
+		}
+		conflicts = append(conflicts, fileConflicts...)
+	}
+	if len(conflicts) > 0 {
+		for _, conflict := range conflicts {
+			fmt.Println(conflict)
+		}
+		return fmt.Errorf("error: found %s, nothing was written", pluralize(len(conflicts), "conflict"))
+	}
+	merged, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		_, err := os.Stdout.Write(merged)
+		return err
+	}
+	if err := os.WriteFile(output, merged, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("merged %d files into [%s]\n", len(files), output)
+	return nil
+}
+
+// maintainersMerge merges maintainers files into doc, remembering the file every team contact and chart came from
+// so that a conflict names the files that disagree
+type maintainersMerge struct {
+	doc    *yaml.Node
+	merged Maintainers
+	// contactFiles are the files the contact fields came from, by team and yaml name of the field
+	contactFiles map[[2]string]string
+	// chartFiles are the files the charts came from, by team, chart and maintainedVersions
+	chartFiles map[[3]string]string
+}
+
+func newMaintainersMerge(doc *yaml.Node, path string) (*maintainersMerge, error) {
+	m := &maintainersMerge{doc: doc, contactFiles: make(map[[2]string]string), chartFiles: make(map[[3]string]string)}
+	if err := doc.Decode(&m.merged); err != nil {
+		return nil, err
+	}
+	for _, team := range m.merged {
+		m.addFiles(team.Name, team.Contact, team.Charts, path)
+	}
+	return m, nil
+}
+
+// addFiles records path as the file of the contact fields that are set and of the charts of team
+func (m *maintainersMerge) addFiles(team string, contact Contact, charts []Chart, path string) {
+	for key, value := range contactFields(contact) {
+		if _, ok := m.contactFiles[[2]string{team, key}]; !ok && value != "" {
+			m.contactFiles[[2]string{team, key}] = path
+		}
+	}
+	for _, chart := range charts {
+		m.chartFiles[[3]string{team, chart.Name, chart.MaintainedVersions}] = path
+	}
+}
+
+// contactFields returns the fields of a contact by their yaml name
+func contactFields(c Contact) map[string]string {
+	return map[string]string{"email": c.Email, "slackChannel": c.SlackChannel, "url": c.URL}
+}
+
+// withContactFields returns c with the fields set, keyed by their yaml name
+func withContactFields(c Contact, fields map[string]string) Contact {
+	for key, value := range fields {
+		switch key {
+		case "email":
+			c.Email = value
+		case "slackChannel":
+			c.SlackChannel = value
+		case "url":
+			c.URL = value
+		}
+	}
+	return c
+}
+
+// add adds the teams and charts of other to the merged file, returning a conflict for every chart of other owned by
+// another team for the same versions or defined differently by the same team, and for every contact field set to
+// different values. Contact fields only one of the files sets are filled, and a chart whose maintainedVersions are
+// split across teams is not a conflict, validate reports the versions of the index their ranges both match
+func (m *maintainersMerge) add(other *yaml.Node, otherPath string) ([]string, error) {
+	var conflicts []string
+	for _, teamNode := range teamNodes(other) {
+		var team Maintainer
+		if err := teamNode.Decode(&team); err != nil {
+			return nil, err
+		}
+		existing, _ := m.merged.FindTeamByName(team.Name)
+		if existing == nil {
+			for _, chart := range team.Charts {
+				if conflict, _ := m.chartConflict(team.Name, chart, otherPath); conflict != "" {
+					conflicts = append(conflicts, conflict)
+				}
+			}
+			m.doc.Content[0].Content = append(m.doc.Content[0].Content, teamNode)
+			m.merged = append(m.merged, &team)
+			m.addFiles(team.Name, team.Contact, team.Charts, otherPath)
+			continue
+		}
+		existingFields, fields := contactFields(existing.Contact), contactFields(team.Contact)
+		filled := make(map[string]string)
+		for _, key := range []string{"email", "slackChannel", "url"} {
+			switch value := fields[key]; {
+			case value == "" || value == existingFields[key]:
+			case existingFields[key] == "":
+				filled[key] = value
+			default:
+				conflicts = append(conflicts, fmt.Sprintf("error: team [%s] has %s [%s] in [%s] but [%s] in [%s]", team.Name, key, existingFields[key], m.contactFiles[[2]string{team.Name, key}], value, otherPath))
+			}
+		}
+		if len(filled) > 0 {
+			for _, t := range teamNodes(m.doc) {
+				if name := mappingValue(t, "name"); name != nil && name.Value == team.Name {
+					setContactNode(t, filled)
+				}
+			}
+			existing.Contact = withContactFields(existing.Contact, filled)
+			m.addFiles(team.Name, withContactFields(Contact{}, filled), nil, otherPath)
+		}
+		charts := mappingValue(teamNode, "charts")
+		for i, chart := range team.Charts {
+			conflict, merged := m.chartConflict(team.Name, chart, otherPath)
+			if conflict != "" {
+				conflicts = append(conflicts, conflict)
+				continue
+			}
+			if merged {
+				continue
+			}
+			if err := insertChartNode(m.doc, team.Name, chart.Name, charts.Content[i]); err != nil {
+				return nil, err
+			}
+			existing.Charts = append(existing.Charts, chart)
+			m.addFiles(team.Name, Contact{}, []Chart{chart}, otherPath)
+		}
+	}
+	return conflicts, nil
+}
+
+// chartConflict returns the conflict of adding chart of team from path, or an empty string and whether the files
+// already merged hold the same chart. Entries of a chart conflict when they maintain the same versions: both all of
+// them, or the same maintainedVersions
+func (m *maintainersMerge) chartConflict(team string, chart Chart, path string) (conflict string, merged bool) {
+	for _, owner := range m.merged {
+		for _, c := range owner.Charts {
+			if c.Name != chart.Name || c.MaintainedVersions != "" && chart.MaintainedVersions != "" && c.MaintainedVersions != chart.MaintainedVersions {
+				continue
+			}
+			file := m.chartFiles[[3]string{owner.Name, c.Name, c.MaintainedVersions}]
+			switch {
+			case owner.Name != team:
+				return fmt.Sprintf("error: chart [%s]%s is maintained by [%s] in [%s] but by [%s] in [%s]", chart.Name, versionsSuffix(chart.MaintainedVersions), owner.Name, file, team, path), false
+			case !reflect.DeepEqual(c, chart):
+				return fmt.Sprintf("error: chart [%s] of [%s] is defined differently in [%s] and [%s]", chart.Name, team, file, path), false
+			}
+			merged = true
+		}
+	}
+	return "", merged
+}
+
+// versionsSuffix describes the maintainedVersions of a chart in a conflict, nothing for all its versions
+func versionsSuffix(maintainedVersions string) string {
+	if maintainedVersions == "" {
+		return ""
+	}
+	return fmt.Sprintf(" version [%s]", maintainedVersions)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestMaintainersMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		// want are the teams of the merged file when there are no conflicts
+		want      Maintainers
+		conflicts []string
+	}{
+		{
+			name: "union",
+			files: []string{
+				"- name: team-a\n  contact:\n    email: a@example.com\n  charts:\n    - name: fleet\n",
+				"- name: team-a\n  charts:\n    - name: fleet\n    - name: fleet-crd\n- name: team-b\n  contact:\n    email: b@example.com\n  charts:\n    - name: rancher-monitoring\n",
+			},
+			want: Maintainers{
+				{Name: "team-a", Contact: Contact{Email: "a@example.com"}, Charts: []Chart{{Name: "fleet"}, {Name: "fleet-crd"}}},
+				{Name: "team-b", Contact: Contact{Email: "b@example.com"}, Charts: []Chart{{Name: "rancher-monitoring"}}},
+			},
+		},
+		{
+			name: "empty contact filled from a later file",
+			files: []string{
+				"- name: team-a\n  charts:\n    - name: fleet\n",
+				"- name: team-a\n  contact:\n    email: a@example.com\n",
+				"- name: team-a\n  contact:\n    email: a@example.com\n    slackChannel: \"#team-a\"\n",
+			},
+			want: Maintainers{
+				{Name: "team-a", Contact: Contact{Email: "a@example.com", SlackChannel: "#team-a"}, Charts: []Chart{{Name: "fleet"}}},
+			},
+		},
+		{
+			name: "maintainedVersions split across teams",
+			files: []string{
+				"- name: team-a\n  charts:\n    - name: fleet\n      maintainedVersions: 104.x\n",
+				"- name: team-b\n  charts:\n    - name: fleet\n      maintainedVersions: 105.x\n",
+			},
+			want: Maintainers{
+				{Name: "team-a", Charts: []Chart{{Name: "fleet", MaintainedVersions: "104.x"}}},
+				{Name: "team-b", Charts: []Chart{{Name: "fleet", MaintainedVersions: "105.x"}}},
+			},
+		},
+		{
+			name: "conflicts name the file the owner came from",
+			files: []string{
+				"- name: team-a\n  charts:\n    - name: fleet\n",
+				"- name: team-b\n  contact:\n    email: b@example.com\n  charts:\n    - name: rancher-monitoring\n      maintainedVersions: 104.x\n",
+				"- name: team-c\n  charts:\n    - name: rancher-monitoring\n      maintainedVersions: 104.x\n    - name: fleet\n      maintainedVersions: 105.x\n" +
+					"- name: team-b\n  contact:\n    email: other@example.com\n",
+			},
+			conflicts: []string{
+				"error: chart [rancher-monitoring] version [104.x] is maintained by [team-b] in [2.yaml] but by [team-c] in [3.yaml]",
+				"error: chart [fleet] version [105.x] is maintained by [team-a] in [1.yaml] but by [team-c] in [3.yaml]",
+				"error: team [team-b] has email [b@example.com] in [2.yaml] but [other@example.com] in [3.yaml]",
+			},
+		},
+		{
+			name: "chart defined differently",
+			files: []string{
+				"- name: team-a\n  charts:\n    - name: fleet\n",
+				"- name: team-b\n",
+				"- name: team-a\n  charts:\n    - name: fleet\n      generateIssue: true\n",
+			},
+			conflicts: []string{
+				"error: chart [fleet] of [team-a] is defined differently in [1.yaml] and [3.yaml]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var docs []*yaml.Node
+			for i, file := range tt.files {
+				path := filepath.Join(dir, string(rune('1'+i))+".yaml")
+				if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				doc, _, err := decodeMaintainersNode(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				docs = append(docs, doc)
+			}
+			merge, err := newMaintainersMerge(docs[0], "1.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var conflicts []string
+			for i, doc := range docs[1:] {
+				fileConflicts, err := merge.add(doc, string(rune('2'+i))+".yaml")
+				if err != nil {
+					t.Fatal(err)
+				}
+				conflicts = append(conflicts, fileConflicts...)
+			}
+			if !reflect.DeepEqual(conflicts, tt.conflicts) {
+				t.Fatalf("got conflicts %q, want %q", conflicts, tt.conflicts)
+			}
+			if tt.conflicts != nil {
+				return
+			}
+			var merged Maintainers
+			if err := docs[0].Decode(&merged); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(merged, tt.want) {
+				t.Errorf("got merged teams %+v, want %+v", merged, tt.want)
+			}
+		})
+	}
+}
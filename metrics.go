@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// serverMetrics are the metrics serve exposes on /metrics: its requests, and the ownership gauges of the last
+// validation of every source, the repository of a webhook delivery or the maintainers file of the API, to alert on
+// ownership drift
+type serverMetrics struct {
+	registry       *prometheus.Registry
+	requests       *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	unowned        *prometheus.GaugeVec
+	findings       *prometheus.GaugeVec
+	lastValidation *prometheus.GaugeVec
+}
+
+func newServerMetrics() *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cowhand_http_requests_total",
+			Help: "HTTP requests served, by handler, method and status code.",
+		}, []string{"handler", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cowhand_http_request_duration_seconds",
+			Help:    "Time taken to serve HTTP requests, by handler.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"handler"}),
+		unowned: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cowhand_unowned_charts",
+			Help: "Charts of the index no team maintains, as of the last validation of the source.",
+		}, []string{"source"}),
+		findings: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cowhand_findings",
+			Help: "Problems found by the last validation of the source, by severity.",
+		}, []string{"source", "severity"}),
+		lastValidation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cowhand_last_successful_validation_timestamp_seconds",
+			Help: "Unix time of the last validation of the source that loaded its files.",
+		}, []string{"source"}),
+	}
+	m.registry.MustRegister(
+		m.requests, m.duration, m.unowned, m.findings, m.lastValidation,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// instrument counts and times the requests h serves under the handler label
+func (m *serverMetrics) instrument(handler string, h http.Handler) http.Handler {
+	labels := prometheus.Labels{"handler": handler}
+	return promhttp.InstrumentHandlerDuration(m.duration.MustCurryWith(labels),
+		promhttp.InstrumentHandlerCounter(m.requests.MustCurryWith(labels), h))
+}
+
+func (m *serverMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// recordValidation sets the ownership gauges of source from the results of a validation that loaded its files
+func (m *serverMetrics) recordValidation(source string, results validate.Results) {
+	unowned := 0
+	bySeverity := map[validate.Severity]int{validate.SeverityError: 0, validate.SeverityWarning: 0}
+	for _, r := range results {
+		if r.Rule == validate.RuleUnownedChart {
+			unowned++
+		}
+		bySeverity[r.Severity]++
+	}
+	m.unowned.WithLabelValues(source).Set(float64(unowned))
+	for severity, count := range bySeverity {
+		m.findings.WithLabelValues(source, string(severity)).Set(float64(count))
+	}
+	m.lastValidation.WithLabelValues(source).Set(float64(time.Now().Unix()))
+}
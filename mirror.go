@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// compareMirror returns a problem for every chart and version present in only one of the primary and mirror
+// indexes, listing the missing versions of a chart together
+func compareMirror(primary, mirror *repo.IndexFile, primaryPath, mirrorPath string) []string {
+	var problems []string
+	problems = append(problems, missingFromIndex(primary, mirror, primaryPath, mirrorPath)...)
+	return append(problems, missingFromIndex(mirror, primary, mirrorPath, primaryPath)...)
+}
+
+// missingFromIndex returns a problem for every chart and version of from that is not in to
+func missingFromIndex(from, to *repo.IndexFile, fromPath, toPath string) []string {
+	var problems []string
+	for _, name := range validate.IndexChartNames(from) {
+		if _, ok := to.Entries[name]; !ok {
+			problems = append(problems, fmt.Sprintf("error: chart [%s] is in index file [%s] but missing from index file [%s]", name, fromPath, toPath))
+			continue
+		}
+		var missing []string
+		for _, cv := range from.Entries[name] {
+			if !to.Has(name, cv.Version) {
+				missing = append(missing, cv.Version)
+			}
+		}
+		if len(missing) > 0 {
+			sortVersions(missing)
+			problems = append(problems, fmt.Sprintf("error: chart [%s] versions [%s] are in index file [%s] but missing from index file [%s]", name, strings.Join(missing, ", "), fromPath, toPath))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runMoveChart(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		to                  string
+		withCRD             bool
+	)
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("move-chart", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&to, "to", "", "name of the team taking over the chart, or a unique part of it such as team/area1")
+	fs.BoolVar(&withCRD, "with-crd", false, "also move the <chart>-crd companion chart")
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" || to == "" {
+		return errors.New("error: usage: cowhand move-chart <chart> --to <team> [--with-crd]")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	target, err := maintainers.FindTeam(to)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	names := []string{chartName}
+	if withCRD {
+		names = append(names, chartName+"-crd")
+	}
+	owners := make(map[string]*Maintainer)
+	for _, name := range names {
+		m, _ := maintainers.FindChart(name)
+		if m == nil {
+			return fmt.Errorf("error: chart [%s] is not in maintainers file [%s]", name, maintainersFilePath)
+		}
+		if m == target {
+			return fmt.Errorf("error: chart [%s] is already maintained by [%s]", name, target.Name)
+		}
+		owners[name] = m
+	}
+
+	// Every chart is moved in memory and the file is written once, so a failure leaves it untouched
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		nodes := extractChartNodes(doc, func(team, chart string) bool { return chart == name })
+		for _, node := range nodes {
+			if err := insertChartNode(doc, target.Name, name, node); err != nil {
+				return err
+			}
+		}
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	// The transfer is printed as markdown so it can be pasted into the pull request description
+	fmt.Println("\nOwnership transfer:")
+	for _, name := range names {
+		fmt.Printf("- `%s` moved from **%s** to **%s**\n", name, owners[name].Name, target.Name)
+	}
+	if labels := teamLabelsToReview(maintainers, owners, names); len(labels) > 0 {
+		fmt.Printf("\nwarning: the moved charts keep their labels [%s], update them if they belong to the previous team\n", strings.Join(labels, ", "))
+	}
+	return nil
+}
+
+// teamLabelsToReview returns the labels of the moved charts that every chart of their previous team shares, those
+// are most likely team labels such as team/area1
+func teamLabelsToReview(maintainers Maintainers, owners map[string]*Maintainer, names []string) []string {
+	var labels []string
+	seen := make(map[string]struct{})
+	for _, name := range names {
+		_, chart := maintainers.FindChart(name)
+		for _, label := range chart.GithubLabels {
+			shared := true
+			for _, c := range owners[name].Charts {
+				found := false
+				for _, l := range c.GithubLabels {
+					found = found || l == label
+				}
+				shared = shared && found
+			}
+			if _, ok := seen[label]; shared && !ok {
+				seen[label] = struct{}{}
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+const (
+	notificationSlack   = "slack"
+	notificationTeams   = "teams"
+	notificationDiscord = "discord"
+	notificationWebhook = "webhook"
+)
+
+// Notification is where daemon reports the changes of the findings: slack posts a message to a Slack incoming
+// webhook, teams posts an adaptive card to a Microsoft Teams workflow webhook, discord posts a message to a Discord
+// webhook and webhook posts the changes as JSON. The URL can name environment variables, e.g. ${SLACK_WEBHOOK_URL},
+// or be read from a Kubernetes Secret, e.g. secret://slack?key=webhook, to keep it out of the config file
+type Notification struct {
+	Type string `yaml:"type"`
+	URL  string `yaml:"url"`
+}
+
+// findingsChange is what changed between two validations, or two digests, the body of the webhook notifications
+type findingsChange struct {
+	Source string `json:"source"`
+	// Digest is the window of the digest the change is, daily or weekly, empty for the immediate notifications
+	Digest   string           `json:"digest,omitempty"`
+	New      validate.Results `json:"new"`
+	Resolved validate.Results `json:"resolved"`
+}
+
+var notifyClient = &http.Client{Timeout: 30 * time.Second, Transport: tracingTransport(http.DefaultTransport)}
+
+// validate returns an error, to follow the position of the notification in the config file, if its type is
+// unknown or it has no URL
+func (n Notification) validate() error {
+	switch n.Type {
+	case notificationSlack, notificationTeams, notificationDiscord, notificationWebhook:
+	default:
+		return fmt.Errorf("of unknown type [%s], use slack, teams, discord or webhook", n.Type)
+	}
+	if n.URL == "" {
+		return errors.New("without a url")
+	}
+	return nil
+}
+
+// slackText is the body of a message posted to a Slack incoming webhook
+type slackText struct {
+	Text string `json:"text"`
+}
+
+// send posts the change to the notification, with the template of its type if there is one
+func (n Notification) send(ctx context.Context, templates *NotificationTemplates, change findingsChange) error {
+	if n.Type == notificationWebhook {
+		return n.post(ctx, change)
+	}
+	body, err := n.body(templates, change.templateData(), change.sections())
+	if err != nil {
+		return err
+	}
+	return n.post(ctx, body)
+}
+
+// sendMessage posts a message meant for a team to the notification, webhook posts it as JSON
+func (n Notification) sendMessage(ctx context.Context, templates *NotificationTemplates, m teamMessage) error {
+	if n.Type != notificationWebhook {
+		body, err := n.body(templates, m.templateData(), []cardSection{m.section()})
+		if err != nil {
+			return err
+		}
+		return n.post(ctx, body)
+	}
+	type item struct {
+		Text string `json:"text"`
+		URL  string `json:"url,omitempty"`
+	}
+	body := struct {
+		Team  string `json:"team"`
+		Title string `json:"title"`
+		Items []item `json:"items"`
+	}{Team: m.team.Name, Title: m.title}
+	for _, i := range m.items {
+		body.Items = append(body.Items, item{i.text, i.url})
+	}
+	return n.post(ctx, body)
+}
+
+// body returns the body of a slack, teams or discord notification, the template of the type replaces the whole
+// text of the built-in sections
+func (n Notification) body(templates *NotificationTemplates, data notificationData, sections []cardSection) (interface{}, error) {
+	text, templated, err := templates.execute(n.Type, data)
+	if err != nil {
+		return nil, err
+	}
+	switch n.Type {
+	case notificationTeams:
+		if templated {
+			return teamsCard(teamsTextBlock{Type: "TextBlock", Text: text, Wrap: true}), nil
+		}
+		return teamsCard(teamsSectionBlocks(sections)...), nil
+	case notificationDiscord:
+		if !templated {
+			text = discordContent(sections)
+		}
+		return discordMessage(text), nil
+	}
+	if !templated {
+		text = slackSectionsText(sections)
+	}
+	return slackText{text}, nil
+}
+
+// post posts the body as JSON to the URL of the notification
+func (n Notification) post(ctx context.Context, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	url, err := resolveSecret(ctx, n.URL)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error: invalid %s notification url: %w", n.Type, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// The errors do not name the URL, it holds the secret of the webhook
+	resp, err := notifyClient.Do(req)
+	if urlErr := (*neturl.Error)(nil); errors.As(err, &urlErr) {
+		return fmt.Errorf("error: %s notification failed: %w", n.Type, urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("error: %s notification failed: %s", n.Type, resp.Status)
+	}
+	return nil
+}
+
+// templateData is what the templates are executed with for the change, which is not meant for a team
+func (c findingsChange) templateData() notificationData {
+	return notificationData{Title: fmt.Sprintf("%s in [%s]", pluralize(len(c.New)+len(c.Resolved), "changed finding"), c.Source), Source: c.Source, Findings: c.New, Resolved: c.Resolved}
+}
+
+// sections returns a section for the new findings and one for the resolved ones, if there are any
+func (c findingsChange) sections() []cardSection {
+	var sections []cardSection
+	for _, part := range []struct {
+		title   string
+		results validate.Results
+	}{
+		{"new", c.New},
+		{"resolved", c.Resolved},
+	} {
+		if len(part.results) == 0 {
+			continue
+		}
+		prefix := "cowhand"
+		if c.Digest != "" {
+			prefix = "cowhand " + c.Digest + " digest"
+		}
+		section := cardSection{title: fmt.Sprintf("%s: %s in [%s]", prefix, pluralize(len(part.results), part.title+" finding"), c.Source)}
+		for _, r := range part.results {
+			section.items = append(section.items, teamMessageItem{text: r.String()})
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// cardSection is a title followed by a list of items, the built-in layout of every notification
+type cardSection struct {
+	title string
+	items []teamMessageItem
+}
+
+// slackSectionsText renders the sections with the links of Slack
+func slackSectionsText(sections []cardSection) string {
+	var b strings.Builder
+	for _, section := range sections {
+		fmt.Fprintf(&b, "%s:\n", section.title)
+		for _, item := range section.items {
+			if item.url != "" {
+				fmt.Fprintf(&b, "• <%s|%s>\n", item.url, item.text)
+			} else {
+				fmt.Fprintf(&b, "• %s\n", item.text)
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+type teamsTextBlock struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Weight  string `json:"weight,omitempty"`
+	Wrap    bool   `json:"wrap"`
+	Spacing string `json:"spacing,omitempty"`
+}
+
+// teamsSectionBlocks renders every section as a bold title followed by a markdown list
+func teamsSectionBlocks(sections []cardSection) []teamsTextBlock {
+	var blocks []teamsTextBlock
+	for _, section := range sections {
+		blocks = append(blocks, teamsTextBlock{Type: "TextBlock", Text: section.title, Weight: "Bolder", Wrap: true, Spacing: "Medium"})
+		blocks = append(blocks, teamsTextBlock{Type: "TextBlock", Text: section.markdownList(), Wrap: true})
+	}
+	return blocks
+}
+
+// teamsCard is the body of a Teams workflow webhook posting an adaptive card of the blocks
+func teamsCard(blocks ...teamsTextBlock) interface{} {
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{map[string]interface{}{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    blocks,
+			},
+		}},
+	}
+}
+
+// markdownList renders the items of the section as a markdown list, linking those with a URL
+func (s cardSection) markdownList() string {
+	var lines []string
+	for _, item := range s.items {
+		if item.url != "" {
+			lines = append(lines, fmt.Sprintf("- [%s](%s)", item.text, item.url))
+		} else {
+			lines = append(lines, "- "+item.text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// discordMaxContent is the most characters Discord accepts in the content of a message
+const discordMaxContent = 2000
+
+// discordContent renders every section as a bold title followed by a markdown list
+func discordContent(sections []cardSection) string {
+	var parts []string
+	for _, section := range sections {
+		parts = append(parts, "**"+section.title+"**\n"+section.markdownList())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// discordMessage is the body of a Discord webhook, cut to the length Discord accepts. Mentions are disabled so that
+// a finding never pings anyone
+func discordMessage(content string) interface{} {
+	runes := []rune(content)
+	if len(runes) > discordMaxContent {
+		runes = append(runes[:discordMaxContent-1], '…')
+	}
+	return map[string]interface{}{
+		"content":          string(runes),
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	}
+}
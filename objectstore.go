@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+const (
+	s3Scheme  = "s3://"
+	gcsScheme = "gs://"
+)
+
+// isObject reports whether uri names an object of a bucket, s3://<bucket>/<key> or gs://<bucket>/<object>
+func isObject(uri string) bool {
+	return strings.HasPrefix(uri, s3Scheme) || strings.HasPrefix(uri, gcsScheme)
+}
+
+// splitObject returns the bucket and the key of an s3:// or gs:// uri
+func splitObject(uri string) (bucket, key string, err error) {
+	_, rest, _ := strings.Cut(uri, "://")
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return "", "", fmt.Errorf("error: [%s] does not name an object, use s3://<bucket>/<key> or gs://<bucket>/<object>", uri)
+	}
+	return bucket, key, nil
+}
+
+// readObject downloads an object from S3 or Google Cloud Storage, see openObject
+func readObject(ctx context.Context, uri string) ([]byte, error) {
+	body, err := openObject(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// openObject starts downloading an object from S3 or Google Cloud Storage and returns its contents, gzip compressed
+// objects are decompressed as they are read like the downloads of openFetch. The clients authenticate with the standard credential chains of their SDKs: the AWS_*
+// variables, the shared config and credentials files, SSO or the role of the instance for S3, and the application
+// default credentials for Google Cloud Storage
+func openObject(ctx context.Context, uri string) (io.ReadCloser, error) {
+	bucket, key, err := splitObject(uri)
+	if err != nil {
+		return nil, err
+	}
+	var body io.ReadCloser
+	if strings.HasPrefix(uri, s3Scheme) {
+		body, err = readS3Object(ctx, bucket, key)
+	} else {
+		body, err = readGCSObject(ctx, bucket, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to read [%s]: %w", uri, err)
+	}
+	return openMaybeGzip(body)
+}
+
+func readS3Object(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// Custom endpoints, e.g. MinIO, usually do not serve a subdomain per bucket
+		o.UsePathStyle = os.Getenv("AWS_ENDPOINT_URL_S3") != "" || os.Getenv("AWS_ENDPOINT_URL") != ""
+		o.DisableLogOutputChecksumValidationSkipped = true
+	})
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if noSuchKey := (*s3types.NoSuchKey)(nil); errors.As(err, &noSuchKey) {
+		return nil, errors.New("no such object")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func readGCSObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := client.Bucket(bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		client.Close()
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, errors.New("no such object")
+		}
+		return nil, err
+	}
+	return gcsObjectReader{r, client}, nil
+}
+
+// gcsObjectReader closes the client along with the object
+type gcsObjectReader struct {
+	*storage.Reader
+	client *storage.Client
+}
+
+func (r gcsObjectReader) Close() error {
+	return errors.Join(r.Reader.Close(), r.client.Close())
+}
+
+// objectIndexSource downloads an index file from a bucket, e.g. s3://charts/index.yaml
+type objectIndexSource struct {
+	uri string
+}
+
+func (s objectIndexSource) Load(ctx context.Context) (*repo.IndexFile, error) {
+	data, err := readObject(ctx, s.uri)
+	if err != nil {
+		return nil, err
+	}
+	return decodeIndex(data, s.uri)
+}
+
+// LoadNames decodes the index as it is downloaded, without holding the whole object
+func (s objectIndexSource) LoadNames(ctx context.Context) (*repo.IndexFile, error) {
+	body, err := openObject(ctx, s.uri)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return decodeIndexNames(body, s.uri)
+}
+
+// readObjectMaintainersFile downloads a maintainers file from a bucket
+func readObjectMaintainersFile(ctx context.Context, uri string) (Maintainers, error) {
+	body, err := openObject(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	ms, err := maintainers.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to decode maintainers file [%s]: %w", uri, err)
+	}
+	return ms, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+const ociScheme = "oci://"
+
+func isOCI(path string) bool {
+	return strings.HasPrefix(path, ociScheme)
+}
+
+// listOCIIndex builds an index from an OCI registry, treating every repository under the namespace of
+// oci://registry/namespace as a chart and every semver tag as one of its versions. The registry must
+// support the catalog API, credentials come from `helm registry login` with docker as a fallback
+func listOCIIndex(ctx context.Context, ref string) (*repo.IndexFile, error) {
+	host, namespace, _ := strings.Cut(strings.TrimPrefix(ref, ociScheme), "/")
+	namespace = strings.Trim(namespace, "/")
+	reg, err := remote.NewRegistry(host)
+	if err != nil {
+		return nil, err
+	}
+	reg.PlainHTTP = strings.HasPrefix(host, "localhost") || strings.HasPrefix(host, "127.0.0.1")
+	reg.Client = &auth.Client{
+		Client:     remoteClient,
+		Cache:      auth.NewCache(),
+		Credential: ociCredentials(),
+	}
+
+	var names []string
+	err = reg.Repositories(ctx, "", func(repos []string) error {
+		for _, name := range repos {
+			if namespace == "" || strings.HasPrefix(name, namespace+"/") {
+				names = append(names, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to list repositories of [%s], the registry must support the catalog API: %w", ref, err)
+	}
+
+	index := repo.NewIndexFile()
+	for _, name := range names {
+		repository, err := reg.Repository(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		chartName := name[strings.LastIndex(name, "/")+1:]
+		err = repository.Tags(ctx, "", func(tags []string) error {
+			for _, tag := range tags {
+				// OCI tags cannot contain '+', helm pushes semver build metadata with '_' instead
+				version := strings.ReplaceAll(tag, "_", "+")
+				if _, err := semver.NewVersion(version); err != nil {
+					continue
+				}
+				index.Entries[chartName] = append(index.Entries[chartName], &repo.ChartVersion{
+					Metadata: &chart.Metadata{Name: chartName, Version: version, APIVersion: chart.APIVersionV2},
+					URLs:     []string{fmt.Sprintf("%s%s/%s:%s", ociScheme, host, name, tag)},
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error: failed to list tags of [%s%s/%s]: %w", ociScheme, host, name, err)
+		}
+	}
+	index.SortEntries()
+	return index, nil
+}
+
+func ociCredentials() auth.CredentialFunc {
+	var stores []credentials.Store
+	configPath := os.Getenv("HELM_REGISTRY_CONFIG")
+	if configPath == "" {
+		configPath = helmpath.ConfigPath("registry/config.json")
+	}
+	if store, err := credentials.NewStore(configPath, credentials.StoreOptions{}); err == nil {
+		stores = append(stores, store)
+	}
+	if store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{}); err == nil {
+		stores = append(stores, store)
+	}
+	if len(stores) == 0 {
+		return auth.StaticCredential("", auth.EmptyCredential)
+	}
+	return credentials.Credential(credentials.NewStoreWithFallbacks(stores[0], stores[1:]...))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runOrphans(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		configFilePath      string
+	)
+	fs := flag.NewFlagSet("orphans", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	index, err := decodeIndexNamesFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	// Only the names are printed and the exit code tells whether there are any, so scripts need no parsing
+	orphans := validate.OrphanCharts(config.Aliases, maintainers, index)
+	for _, name := range orphans {
+		fmt.Println(name)
+	}
+	if len(orphans) > 0 {
+		return exitCode(1)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// ownersFile is a prow style OWNERS file, see https://www.kubernetes.dev/docs/guide/owners/
+type ownersFile struct {
+	Approvers []string `yaml:"approvers"`
+	Reviewers []string `yaml:"reviewers,omitempty"`
+	Labels    []string `yaml:"labels,omitempty"`
+}
+
+const ownersFileHeader = "# Generated by cowhand generate owners from the maintainers file, do not edit\n"
+
+func runGenerateOwners(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		configFilePath      string
+		chartsDir           string
+		apply               bool
+	)
+	fs := flag.NewFlagSet("generate owners", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the handles of every team")
+	fs.StringVar(&chartsDir, "charts-dir", "./charts", "directory holding a directory per chart the OWNERS files are written to")
+	fs.BoolVar(&apply, "apply", false, "write the OWNERS files instead of only printing the plan")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	fs.Parse(args)
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	p, warnings, err := planOwnersFiles(config, maintainers, chartsDir)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Println(warning)
+	}
+	p.print(os.Stdout)
+	if !apply || p.empty() {
+		if !p.empty() {
+			fmt.Println("\nRe-run with --apply to write these changes")
+		}
+		return nil
+	}
+	fmt.Println()
+	return p.apply(os.Stdout)
+}
+
+// planOwnersFiles plans writing an OWNERS file in the directory of every chart under chartsDir, with the handles of
+// the team maintaining it as approvers and reviewers and its GitHub labels. Directories of charts no team maintains
+// and charts of teams without handles are left alone with a warning
+func planOwnersFiles(config *Config, maintainers Maintainers, chartsDir string) (*plan, []string, error) {
+	entries, err := os.ReadDir(chartsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings []string
+	for team := range config.Handles {
+		if m, _ := maintainers.FindTeamByName(team); m == nil {
+			warnings = append(warnings, fmt.Sprintf("warning: config has handles for team [%s] which is not in the maintainers file", team))
+		}
+	}
+	sort.Strings(warnings)
+	missingHandles := make(map[string]struct{})
+	p := &plan{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		m, chart := maintainers.FindChart(validate.CanonicalChartName(config.Aliases, name))
+		if m == nil {
+			warnings = append(warnings, fmt.Sprintf("warning: chart [%s] in [%s] is in no team of the maintainers file, it gets no OWNERS file", name, chartsDir))
+			continue
+		}
+		handles, ok := config.Handles[m.Name]
+		if !ok || len(handles.Approvers) == 0 {
+			if _, warned := missingHandles[m.Name]; !warned {
+				missingHandles[m.Name] = struct{}{}
+				warnings = append(warnings, fmt.Sprintf("warning: team [%s] has no approvers in the handles of the config, its charts get no OWNERS file", m.Name))
+			}
+			continue
+		}
+		owners := ownersFile{Approvers: handles.Approvers, Reviewers: handles.Reviewers, Labels: chart.GithubLabels}
+		if len(owners.Reviewers) == 0 {
+			owners.Reviewers = owners.Approvers
+		}
+		var buf bytes.Buffer
+		buf.WriteString(ownersFileHeader)
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(owners); err != nil {
+			return nil, nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, nil, err
+		}
+		path := filepath.Join(chartsDir, name, "OWNERS")
+		out := buf.Bytes()
+		current, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			p.add(planCreate, "OWNERS file", fmt.Sprintf("[%s] for [%s]", path, m.Name), func() error {
+				return os.WriteFile(path, out, 0o644)
+			})
+		case err != nil:
+			return nil, nil, err
+		case bytes.Equal(current, out):
+			p.add(planSkip, "OWNERS file", fmt.Sprintf("[%s]", path), nil)
+		default:
+			p.add(planUpdate, "OWNERS file", fmt.Sprintf("[%s] for [%s]", path, m.Name), func() error {
+				return os.WriteFile(path, out, 0o644)
+			})
+		}
+	}
+	return p, warnings, nil
+}
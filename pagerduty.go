@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// pagerDutyEventsURL is the endpoint of the PagerDuty Events API v2
+var pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty pages through an Events API v2 integration when a critical chart of the config is not maintained by any
+// team or its team has no contact. Like the notification URLs, the routing key can name environment variables
+type PagerDuty struct {
+	RoutingKey string `yaml:"routingKey"`
+	// Severity of the events, critical if it is not set
+	Severity string `yaml:"severity"`
+}
+
+// pagerDutyEvent is the body of an event, payload is only set on triggers
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Component string `json:"component"`
+	Class     string `json:"class"`
+}
+
+// criticalCharts returns the charts of the index matching one of the critical patterns of the config, sorted
+func criticalCharts(config *Config, index *repo.IndexFile) []string {
+	var charts []string
+	for _, name := range validate.IndexChartNames(index) {
+		for _, pattern := range config.Critical {
+			if ok, _ := path.Match(pattern, name); ok {
+				charts = append(charts, name)
+				break
+			}
+		}
+	}
+	sort.Strings(charts)
+	return charts
+}
+
+// ownershipGaps maps the critical charts that are not maintained by any team, or only by the unassigned team, or
+// whose team has no contact at all, to a summary of the gap
+func ownershipGaps(config *Config, maintainers Maintainers, index *repo.IndexFile) map[string]string {
+	teams := make(map[string]*Maintainer)
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			name := validate.CanonicalChartName(config.Aliases, chart.Name)
+			if _, ok := teams[name]; !ok {
+				teams[name] = m
+			}
+		}
+	}
+	gaps := make(map[string]string)
+	for _, name := range criticalCharts(config, index) {
+		m := teams[name]
+		switch {
+		case m == nil || m.Name == config.unassignedTeam():
+			gaps[name] = fmt.Sprintf("critical chart [%s] is not maintained by any team", name)
+		case m.Contact.Email == "" && m.Contact.SlackChannel == "" && m.Contact.URL == "":
+			gaps[name] = fmt.Sprintf("critical chart [%s] is maintained by team [%s] which has no contact", name, m.Name)
+		}
+	}
+	return gaps
+}
+
+// pageCriticalCharts triggers an event for every ownership gap of the critical charts and resolves the event of
+// every other critical chart. The dedup key of a chart is stable so that triggering a gap again does not page again,
+// and resolving a chart that never paged does nothing, which keeps it stateless
+func pageCriticalCharts(ctx context.Context, config *Config, maintainers Maintainers, index *repo.IndexFile, source string) error {
+	routingKey, err := resolveSecret(ctx, config.PagerDuty.RoutingKey)
+	if err != nil {
+		return err
+	}
+	if routingKey == "" {
+		return errors.New("error: no pagerDuty routingKey in the config file")
+	}
+	severity := config.PagerDuty.Severity
+	if severity == "" {
+		severity = "critical"
+	}
+	gaps := ownershipGaps(config, maintainers, index)
+	var failed int
+	for _, chart := range criticalCharts(config, index) {
+		event := pagerDutyEvent{RoutingKey: routingKey, EventAction: "resolve", DedupKey: "cowhand/" + source + "/" + chart}
+		if summary, ok := gaps[chart]; ok {
+			event.EventAction = "trigger"
+			event.Payload = &pagerDutyPayload{Summary: summary, Source: source, Severity: severity, Component: chart, Class: "ownership"}
+		}
+		if err := sendPagerDutyEvent(ctx, event); err != nil {
+			slog.Error("failed to send pagerduty event", "chart", chart, "action", event.EventAction, "error", err)
+			failed++
+			continue
+		}
+		if event.EventAction == "trigger" {
+			slog.Warn("paged", "chart", chart, "summary", event.Payload.Summary)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("error: failed to send %s", pluralize(failed, "pagerduty event"))
+	}
+	return nil
+}
+
+func sendPagerDutyEvent(ctx context.Context, event pagerDutyEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pagerDutyEventsURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := notifyClient.Do(req)
+	if urlErr := (*neturl.Error)(nil); errors.As(err, &urlErr) {
+		return fmt.Errorf("error: pagerduty event failed: %w", urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("error: pagerduty event failed: %s", resp.Status)
+	}
+	return nil
+}
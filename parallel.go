@@ -0,0 +1,34 @@
+package main
+
+import "sync"
+
+// defaultParallelism is how many repositories or branches are loaded and validated at once by default, they are
+// mostly waiting on the network or git
+const defaultParallelism = 4
+
+// parallel calls fn for every i in [0, n) from at most workers goroutines and returns once every call is done.
+// Callers store the result of i at index i so the output keeps the order of the inputs
+func parallel(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
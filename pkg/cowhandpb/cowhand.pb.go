@@ -0,0 +1,957 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: cowhand.proto
+
+package cowhandpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_ERROR       Severity = 1
+	Severity_SEVERITY_WARNING     Severity = 2
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_ERROR",
+		2: "SEVERITY_WARNING",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_ERROR":       1,
+		"SEVERITY_WARNING":     2,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_cowhand_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_cowhand_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{0}
+}
+
+type Contact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	SlackChannel  string                 `protobuf:"bytes,2,opt,name=slack_channel,json=slackChannel,proto3" json:"slack_channel,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	mi := &file_cowhand_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Contact) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Contact) GetSlackChannel() string {
+	if x != nil {
+		return x.SlackChannel
+	}
+	return ""
+}
+
+func (x *Contact) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Chart struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Name                     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	GenerateIssue            bool                   `protobuf:"varint,2,opt,name=generate_issue,json=generateIssue,proto3" json:"generate_issue,omitempty"`
+	GithubLabels             []string               `protobuf:"bytes,3,rep,name=github_labels,json=githubLabels,proto3" json:"github_labels,omitempty"`
+	Repositories             []string               `protobuf:"bytes,4,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	AcknowledgedDependencies []string               `protobuf:"bytes,5,rep,name=acknowledged_dependencies,json=acknowledgedDependencies,proto3" json:"acknowledged_dependencies,omitempty"`
+	MaintainedVersions       string                 `protobuf:"bytes,6,opt,name=maintained_versions,json=maintainedVersions,proto3" json:"maintained_versions,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Chart) Reset() {
+	*x = Chart{}
+	mi := &file_cowhand_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chart) ProtoMessage() {}
+
+func (x *Chart) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chart.ProtoReflect.Descriptor instead.
+func (*Chart) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Chart) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Chart) GetGenerateIssue() bool {
+	if x != nil {
+		return x.GenerateIssue
+	}
+	return false
+}
+
+func (x *Chart) GetGithubLabels() []string {
+	if x != nil {
+		return x.GithubLabels
+	}
+	return nil
+}
+
+func (x *Chart) GetRepositories() []string {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+func (x *Chart) GetAcknowledgedDependencies() []string {
+	if x != nil {
+		return x.AcknowledgedDependencies
+	}
+	return nil
+}
+
+func (x *Chart) GetMaintainedVersions() string {
+	if x != nil {
+		return x.MaintainedVersions
+	}
+	return ""
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Contact       *Contact               `protobuf:"bytes,2,opt,name=contact,proto3" json:"contact,omitempty"`
+	Charts        []*Chart               `protobuf:"bytes,3,rep,name=charts,proto3" json:"charts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_cowhand_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Team) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *Team) GetCharts() []*Chart {
+	if x != nil {
+		return x.Charts
+	}
+	return nil
+}
+
+type TeamSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Contact       *Contact               `protobuf:"bytes,2,opt,name=contact,proto3" json:"contact,omitempty"`
+	Charts        int32                  `protobuf:"varint,3,opt,name=charts,proto3" json:"charts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamSummary) Reset() {
+	*x = TeamSummary{}
+	mi := &file_cowhand_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamSummary) ProtoMessage() {}
+
+func (x *TeamSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamSummary.ProtoReflect.Descriptor instead.
+func (*TeamSummary) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TeamSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TeamSummary) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *TeamSummary) GetCharts() int32 {
+	if x != nil {
+		return x.Charts
+	}
+	return 0
+}
+
+type ListTeamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeamsRequest) Reset() {
+	*x = ListTeamsRequest{}
+	mi := &file_cowhand_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamsRequest) ProtoMessage() {}
+
+func (x *ListTeamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamsRequest.ProtoReflect.Descriptor instead.
+func (*ListTeamsRequest) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{4}
+}
+
+type ListTeamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Teams         []*TeamSummary         `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTeamsResponse) Reset() {
+	*x = ListTeamsResponse{}
+	mi := &file_cowhand_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTeamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTeamsResponse) ProtoMessage() {}
+
+func (x *ListTeamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTeamsResponse.ProtoReflect.Descriptor instead.
+func (*ListTeamsResponse) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTeamsResponse) GetTeams() []*TeamSummary {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type GetTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamRequest) Reset() {
+	*x = GetTeamRequest{}
+	mi := &file_cowhand_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamRequest) ProtoMessage() {}
+
+func (x *GetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetTeamRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetChartMaintainerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Chart string                 `protobuf:"bytes,1,opt,name=chart,proto3" json:"chart,omitempty"`
+	// version is optional, the team maintaining that version of the chart is returned
+	Version       string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChartMaintainerRequest) Reset() {
+	*x = GetChartMaintainerRequest{}
+	mi := &file_cowhand_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChartMaintainerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChartMaintainerRequest) ProtoMessage() {}
+
+func (x *GetChartMaintainerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChartMaintainerRequest.ProtoReflect.Descriptor instead.
+func (*GetChartMaintainerRequest) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetChartMaintainerRequest) GetChart() string {
+	if x != nil {
+		return x.Chart
+	}
+	return ""
+}
+
+func (x *GetChartMaintainerRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type ChartMaintainer struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Chart              string                 `protobuf:"bytes,1,opt,name=chart,proto3" json:"chart,omitempty"`
+	Team               string                 `protobuf:"bytes,2,opt,name=team,proto3" json:"team,omitempty"`
+	Contact            *Contact               `protobuf:"bytes,3,opt,name=contact,proto3" json:"contact,omitempty"`
+	MaintainedVersions string                 `protobuf:"bytes,4,opt,name=maintained_versions,json=maintainedVersions,proto3" json:"maintained_versions,omitempty"`
+	Repositories       []string               `protobuf:"bytes,5,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	GithubLabels       []string               `protobuf:"bytes,6,rep,name=github_labels,json=githubLabels,proto3" json:"github_labels,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ChartMaintainer) Reset() {
+	*x = ChartMaintainer{}
+	mi := &file_cowhand_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChartMaintainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChartMaintainer) ProtoMessage() {}
+
+func (x *ChartMaintainer) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChartMaintainer.ProtoReflect.Descriptor instead.
+func (*ChartMaintainer) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChartMaintainer) GetChart() string {
+	if x != nil {
+		return x.Chart
+	}
+	return ""
+}
+
+func (x *ChartMaintainer) GetTeam() string {
+	if x != nil {
+		return x.Team
+	}
+	return ""
+}
+
+func (x *ChartMaintainer) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *ChartMaintainer) GetMaintainedVersions() string {
+	if x != nil {
+		return x.MaintainedVersions
+	}
+	return ""
+}
+
+func (x *ChartMaintainer) GetRepositories() []string {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+func (x *ChartMaintainer) GetGithubLabels() []string {
+	if x != nil {
+		return x.GithubLabels
+	}
+	return nil
+}
+
+type ValidateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// maintainers_file is the content of the maintainers file
+	MaintainersFile []byte `protobuf:"bytes,1,opt,name=maintainers_file,json=maintainersFile,proto3" json:"maintainers_file,omitempty"`
+	// The index is optional, without one the maintainers file is only validated on its own
+	//
+	// Types that are valid to be assigned to Index:
+	//
+	//	*ValidateRequest_IndexFile
+	//	*ValidateRequest_IndexUrl
+	Index         isValidateRequest_Index `protobuf_oneof:"index"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_cowhand_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ValidateRequest) GetMaintainersFile() []byte {
+	if x != nil {
+		return x.MaintainersFile
+	}
+	return nil
+}
+
+func (x *ValidateRequest) GetIndex() isValidateRequest_Index {
+	if x != nil {
+		return x.Index
+	}
+	return nil
+}
+
+func (x *ValidateRequest) GetIndexFile() []byte {
+	if x != nil {
+		if x, ok := x.Index.(*ValidateRequest_IndexFile); ok {
+			return x.IndexFile
+		}
+	}
+	return nil
+}
+
+func (x *ValidateRequest) GetIndexUrl() string {
+	if x != nil {
+		if x, ok := x.Index.(*ValidateRequest_IndexUrl); ok {
+			return x.IndexUrl
+		}
+	}
+	return ""
+}
+
+type isValidateRequest_Index interface {
+	isValidateRequest_Index()
+}
+
+type ValidateRequest_IndexFile struct {
+	// index_file is the content of the index file
+	IndexFile []byte `protobuf:"bytes,2,opt,name=index_file,json=indexFile,proto3,oneof"`
+}
+
+type ValidateRequest_IndexUrl struct {
+	// index_url is the http(s) or oci:// URL of an index or of a chart repository
+	IndexUrl string `protobuf:"bytes,3,opt,name=index_url,json=indexUrl,proto3,oneof"`
+}
+
+func (*ValidateRequest_IndexFile) isValidateRequest_Index() {}
+
+func (*ValidateRequest_IndexUrl) isValidateRequest_Index() {}
+
+type Finding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Severity      Severity               `protobuf:"varint,2,opt,name=severity,proto3,enum=cowhand.v1.Severity" json:"severity,omitempty"`
+	Chart         string                 `protobuf:"bytes,3,opt,name=chart,proto3" json:"chart,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Finding) Reset() {
+	*x = Finding{}
+	mi := &file_cowhand_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Finding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finding) ProtoMessage() {}
+
+func (x *Finding) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finding.ProtoReflect.Descriptor instead.
+func (*Finding) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Finding) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Finding) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Finding) GetChart() string {
+	if x != nil {
+		return x.Chart
+	}
+	return ""
+}
+
+func (x *Finding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ValidateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// valid is false when a finding is an error
+	Valid         bool       `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Findings      []*Finding `protobuf:"bytes,2,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_cowhand_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cowhand_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_cowhand_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetFindings() []*Finding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+var File_cowhand_proto protoreflect.FileDescriptor
+
+const file_cowhand_proto_rawDesc = "" +
+	"\n" +
+	"\rcowhand.proto\x12\n" +
+	"cowhand.v1\"V\n" +
+	"\aContact\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12#\n" +
+	"\rslack_channel\x18\x02 \x01(\tR\fslackChannel\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\"\xf9\x01\n" +
+	"\x05Chart\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0egenerate_issue\x18\x02 \x01(\bR\rgenerateIssue\x12#\n" +
+	"\rgithub_labels\x18\x03 \x03(\tR\fgithubLabels\x12\"\n" +
+	"\frepositories\x18\x04 \x03(\tR\frepositories\x12;\n" +
+	"\x19acknowledged_dependencies\x18\x05 \x03(\tR\x18acknowledgedDependencies\x12/\n" +
+	"\x13maintained_versions\x18\x06 \x01(\tR\x12maintainedVersions\"t\n" +
+	"\x04Team\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\acontact\x18\x02 \x01(\v2\x13.cowhand.v1.ContactR\acontact\x12)\n" +
+	"\x06charts\x18\x03 \x03(\v2\x11.cowhand.v1.ChartR\x06charts\"h\n" +
+	"\vTeamSummary\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\acontact\x18\x02 \x01(\v2\x13.cowhand.v1.ContactR\acontact\x12\x16\n" +
+	"\x06charts\x18\x03 \x01(\x05R\x06charts\"\x12\n" +
+	"\x10ListTeamsRequest\"B\n" +
+	"\x11ListTeamsResponse\x12-\n" +
+	"\x05teams\x18\x01 \x03(\v2\x17.cowhand.v1.TeamSummaryR\x05teams\"$\n" +
+	"\x0eGetTeamRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"K\n" +
+	"\x19GetChartMaintainerRequest\x12\x14\n" +
+	"\x05chart\x18\x01 \x01(\tR\x05chart\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\xe4\x01\n" +
+	"\x0fChartMaintainer\x12\x14\n" +
+	"\x05chart\x18\x01 \x01(\tR\x05chart\x12\x12\n" +
+	"\x04team\x18\x02 \x01(\tR\x04team\x12-\n" +
+	"\acontact\x18\x03 \x01(\v2\x13.cowhand.v1.ContactR\acontact\x12/\n" +
+	"\x13maintained_versions\x18\x04 \x01(\tR\x12maintainedVersions\x12\"\n" +
+	"\frepositories\x18\x05 \x03(\tR\frepositories\x12#\n" +
+	"\rgithub_labels\x18\x06 \x03(\tR\fgithubLabels\"\x85\x01\n" +
+	"\x0fValidateRequest\x12)\n" +
+	"\x10maintainers_file\x18\x01 \x01(\fR\x0fmaintainersFile\x12\x1f\n" +
+	"\n" +
+	"index_file\x18\x02 \x01(\fH\x00R\tindexFile\x12\x1d\n" +
+	"\tindex_url\x18\x03 \x01(\tH\x00R\bindexUrlB\a\n" +
+	"\x05index\"\x7f\n" +
+	"\aFinding\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x120\n" +
+	"\bseverity\x18\x02 \x01(\x0e2\x14.cowhand.v1.SeverityR\bseverity\x12\x14\n" +
+	"\x05chart\x18\x03 \x01(\tR\x05chart\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"Y\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12/\n" +
+	"\bfindings\x18\x02 \x03(\v2\x13.cowhand.v1.FindingR\bfindings*N\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSEVERITY_ERROR\x10\x01\x12\x14\n" +
+	"\x10SEVERITY_WARNING\x10\x022\xad\x02\n" +
+	"\aCowhand\x12H\n" +
+	"\tListTeams\x12\x1c.cowhand.v1.ListTeamsRequest\x1a\x1d.cowhand.v1.ListTeamsResponse\x127\n" +
+	"\aGetTeam\x12\x1a.cowhand.v1.GetTeamRequest\x1a\x10.cowhand.v1.Team\x12X\n" +
+	"\x12GetChartMaintainer\x12%.cowhand.v1.GetChartMaintainerRequest\x1a\x1b.cowhand.v1.ChartMaintainer\x12E\n" +
+	"\bValidate\x12\x1b.cowhand.v1.ValidateRequest\x1a\x1c.cowhand.v1.ValidateResponseB6Z4github.com/pennyscissors/go-playground/pkg/cowhandpbb\x06proto3"
+
+var (
+	file_cowhand_proto_rawDescOnce sync.Once
+	file_cowhand_proto_rawDescData []byte
+)
+
+func file_cowhand_proto_rawDescGZIP() []byte {
+	file_cowhand_proto_rawDescOnce.Do(func() {
+		file_cowhand_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cowhand_proto_rawDesc), len(file_cowhand_proto_rawDesc)))
+	})
+	return file_cowhand_proto_rawDescData
+}
+
+var file_cowhand_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cowhand_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_cowhand_proto_goTypes = []any{
+	(Severity)(0),                     // 0: cowhand.v1.Severity
+	(*Contact)(nil),                   // 1: cowhand.v1.Contact
+	(*Chart)(nil),                     // 2: cowhand.v1.Chart
+	(*Team)(nil),                      // 3: cowhand.v1.Team
+	(*TeamSummary)(nil),               // 4: cowhand.v1.TeamSummary
+	(*ListTeamsRequest)(nil),          // 5: cowhand.v1.ListTeamsRequest
+	(*ListTeamsResponse)(nil),         // 6: cowhand.v1.ListTeamsResponse
+	(*GetTeamRequest)(nil),            // 7: cowhand.v1.GetTeamRequest
+	(*GetChartMaintainerRequest)(nil), // 8: cowhand.v1.GetChartMaintainerRequest
+	(*ChartMaintainer)(nil),           // 9: cowhand.v1.ChartMaintainer
+	(*ValidateRequest)(nil),           // 10: cowhand.v1.ValidateRequest
+	(*Finding)(nil),                   // 11: cowhand.v1.Finding
+	(*ValidateResponse)(nil),          // 12: cowhand.v1.ValidateResponse
+}
+var file_cowhand_proto_depIdxs = []int32{
+	1,  // 0: cowhand.v1.Team.contact:type_name -> cowhand.v1.Contact
+	2,  // 1: cowhand.v1.Team.charts:type_name -> cowhand.v1.Chart
+	1,  // 2: cowhand.v1.TeamSummary.contact:type_name -> cowhand.v1.Contact
+	4,  // 3: cowhand.v1.ListTeamsResponse.teams:type_name -> cowhand.v1.TeamSummary
+	1,  // 4: cowhand.v1.ChartMaintainer.contact:type_name -> cowhand.v1.Contact
+	0,  // 5: cowhand.v1.Finding.severity:type_name -> cowhand.v1.Severity
+	11, // 6: cowhand.v1.ValidateResponse.findings:type_name -> cowhand.v1.Finding
+	5,  // 7: cowhand.v1.Cowhand.ListTeams:input_type -> cowhand.v1.ListTeamsRequest
+	7,  // 8: cowhand.v1.Cowhand.GetTeam:input_type -> cowhand.v1.GetTeamRequest
+	8,  // 9: cowhand.v1.Cowhand.GetChartMaintainer:input_type -> cowhand.v1.GetChartMaintainerRequest
+	10, // 10: cowhand.v1.Cowhand.Validate:input_type -> cowhand.v1.ValidateRequest
+	6,  // 11: cowhand.v1.Cowhand.ListTeams:output_type -> cowhand.v1.ListTeamsResponse
+	3,  // 12: cowhand.v1.Cowhand.GetTeam:output_type -> cowhand.v1.Team
+	9,  // 13: cowhand.v1.Cowhand.GetChartMaintainer:output_type -> cowhand.v1.ChartMaintainer
+	12, // 14: cowhand.v1.Cowhand.Validate:output_type -> cowhand.v1.ValidateResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_cowhand_proto_init() }
+func file_cowhand_proto_init() {
+	if File_cowhand_proto != nil {
+		return
+	}
+	file_cowhand_proto_msgTypes[9].OneofWrappers = []any{
+		(*ValidateRequest_IndexFile)(nil),
+		(*ValidateRequest_IndexUrl)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cowhand_proto_rawDesc), len(file_cowhand_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cowhand_proto_goTypes,
+		DependencyIndexes: file_cowhand_proto_depIdxs,
+		EnumInfos:         file_cowhand_proto_enumTypes,
+		MessageInfos:      file_cowhand_proto_msgTypes,
+	}.Build()
+	File_cowhand_proto = out.File
+	file_cowhand_proto_goTypes = nil
+	file_cowhand_proto_depIdxs = nil
+}
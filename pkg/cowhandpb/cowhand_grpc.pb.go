@@ -0,0 +1,253 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: cowhand.proto
+
+package cowhandpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Cowhand_ListTeams_FullMethodName          = "/cowhand.v1.Cowhand/ListTeams"
+	Cowhand_GetTeam_FullMethodName            = "/cowhand.v1.Cowhand/GetTeam"
+	Cowhand_GetChartMaintainer_FullMethodName = "/cowhand.v1.Cowhand/GetChartMaintainer"
+	Cowhand_Validate_FullMethodName           = "/cowhand.v1.Cowhand/Validate"
+)
+
+// CowhandClient is the client API for Cowhand service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Cowhand is the gRPC API of cowhand serve, mirroring the /v1 HTTP endpoints: the ownership queries answered from
+// the maintainers file the server reloads, and the validation of maintainers files sent by clients
+type CowhandClient interface {
+	// ListTeams returns every team of the maintainers file
+	ListTeams(ctx context.Context, in *ListTeamsRequest, opts ...grpc.CallOption) (*ListTeamsResponse, error)
+	// GetTeam returns a team with its charts, NOT_FOUND if no team has the name
+	GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*Team, error)
+	// GetChartMaintainer returns the team maintaining a chart, also found by one of its aliases, NOT_FOUND if no team
+	// maintains it
+	GetChartMaintainer(ctx context.Context, in *GetChartMaintainerRequest, opts ...grpc.CallOption) (*ChartMaintainer, error)
+	// Validate validates a maintainers file on its own or against an index, the calls carry the token of the server as
+	// a bearer token in their authorization metadata, UNAUTHENTICATED otherwise
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+}
+
+type cowhandClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCowhandClient(cc grpc.ClientConnInterface) CowhandClient {
+	return &cowhandClient{cc}
+}
+
+func (c *cowhandClient) ListTeams(ctx context.Context, in *ListTeamsRequest, opts ...grpc.CallOption) (*ListTeamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTeamsResponse)
+	err := c.cc.Invoke(ctx, Cowhand_ListTeams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cowhandClient) GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*Team, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Team)
+	err := c.cc.Invoke(ctx, Cowhand_GetTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cowhandClient) GetChartMaintainer(ctx context.Context, in *GetChartMaintainerRequest, opts ...grpc.CallOption) (*ChartMaintainer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChartMaintainer)
+	err := c.cc.Invoke(ctx, Cowhand_GetChartMaintainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cowhandClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, Cowhand_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CowhandServer is the server API for Cowhand service.
+// All implementations must embed UnimplementedCowhandServer
+// for forward compatibility.
+//
+// Cowhand is the gRPC API of cowhand serve, mirroring the /v1 HTTP endpoints: the ownership queries answered from
+// the maintainers file the server reloads, and the validation of maintainers files sent by clients
+type CowhandServer interface {
+	// ListTeams returns every team of the maintainers file
+	ListTeams(context.Context, *ListTeamsRequest) (*ListTeamsResponse, error)
+	// GetTeam returns a team with its charts, NOT_FOUND if no team has the name
+	GetTeam(context.Context, *GetTeamRequest) (*Team, error)
+	// GetChartMaintainer returns the team maintaining a chart, also found by one of its aliases, NOT_FOUND if no team
+	// maintains it
+	GetChartMaintainer(context.Context, *GetChartMaintainerRequest) (*ChartMaintainer, error)
+	// Validate validates a maintainers file on its own or against an index, the calls carry the token of the server as
+	// a bearer token in their authorization metadata, UNAUTHENTICATED otherwise
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	mustEmbedUnimplementedCowhandServer()
+}
+
+// UnimplementedCowhandServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCowhandServer struct{}
+
+func (UnimplementedCowhandServer) ListTeams(context.Context, *ListTeamsRequest) (*ListTeamsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTeams not implemented")
+}
+func (UnimplementedCowhandServer) GetTeam(context.Context, *GetTeamRequest) (*Team, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTeam not implemented")
+}
+func (UnimplementedCowhandServer) GetChartMaintainer(context.Context, *GetChartMaintainerRequest) (*ChartMaintainer, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetChartMaintainer not implemented")
+}
+func (UnimplementedCowhandServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedCowhandServer) mustEmbedUnimplementedCowhandServer() {}
+func (UnimplementedCowhandServer) testEmbeddedByValue()                 {}
+
+// UnsafeCowhandServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CowhandServer will
+// result in compilation errors.
+type UnsafeCowhandServer interface {
+	mustEmbedUnimplementedCowhandServer()
+}
+
+func RegisterCowhandServer(s grpc.ServiceRegistrar, srv CowhandServer) {
+	// If the following call panics, it indicates UnimplementedCowhandServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Cowhand_ServiceDesc, srv)
+}
+
+func _Cowhand_ListTeams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTeamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CowhandServer).ListTeams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cowhand_ListTeams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CowhandServer).ListTeams(ctx, req.(*ListTeamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cowhand_GetTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CowhandServer).GetTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cowhand_GetTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CowhandServer).GetTeam(ctx, req.(*GetTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cowhand_GetChartMaintainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChartMaintainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CowhandServer).GetChartMaintainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cowhand_GetChartMaintainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CowhandServer).GetChartMaintainer(ctx, req.(*GetChartMaintainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cowhand_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CowhandServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cowhand_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CowhandServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Cowhand_ServiceDesc is the grpc.ServiceDesc for Cowhand service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Cowhand_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cowhand.v1.Cowhand",
+	HandlerType: (*CowhandServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTeams",
+			Handler:    _Cowhand_ListTeams_Handler,
+		},
+		{
+			MethodName: "GetTeam",
+			Handler:    _Cowhand_GetTeam_Handler,
+		},
+		{
+			MethodName: "GetChartMaintainer",
+			Handler:    _Cowhand_GetChartMaintainer_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _Cowhand_Validate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cowhand.proto",
+}
@@ -0,0 +1,5 @@
+// Package cowhandpb is the generated protobuf and gRPC code of the API cowhand serve exposes with --grpc-listen,
+// cowhand.proto is its definition for clients in other languages
+package cowhandpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cowhand.proto
@@ -0,0 +1,159 @@
+// Package cowhandtest helps write regression tests for the validation rules and for rule plugins: it ships a corpus
+// of maintainers and index fixtures with the golden findings validate.Run reports on them, and compares findings to
+// golden files. Run the tests with -cowhandtest.update to write the golden files instead of comparing them
+package cowhandtest
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/repo"
+	sigyaml "sigs.k8s.io/yaml"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+var update = flag.Bool("cowhandtest.update", false, "write the golden files of cowhandtest instead of comparing them")
+
+//go:embed testdata
+var testdata embed.FS
+
+// Fixtures is the corpus, a directory per case holding a maintainers.yaml, an index.yaml and the findings.golden
+// validate.Run reports on them with the default options
+func Fixtures() fs.FS {
+	fsys, err := fs.Sub(testdata, "testdata")
+	if err != nil {
+		panic(err)
+	}
+	return fsys
+}
+
+// Case is a case of the corpus
+type Case struct {
+	Name        string
+	Maintainers maintainers.Maintainers
+	Index       *repo.IndexFile
+	// Golden is the expected findings in the format of FormatResults
+	Golden []byte
+}
+
+// Cases loads every case of the corpus, in the lexical order of their names
+func Cases(t testing.TB) []Case {
+	t.Helper()
+	fsys := Fixtures()
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		t.Fatalf("failed to list the cowhandtest fixtures: %v", err)
+	}
+	var cases []Case
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		golden, err := fs.ReadFile(fsys, path.Join(entry.Name(), "findings.golden"))
+		if err != nil {
+			t.Fatalf("failed to read the golden findings of case [%s]: %v", entry.Name(), err)
+		}
+		cases = append(cases, Case{
+			Name:        entry.Name(),
+			Maintainers: Maintainers(t, fsys, path.Join(entry.Name(), "maintainers.yaml")),
+			Index:       Index(t, fsys, path.Join(entry.Name(), "index.yaml")),
+			Golden:      golden,
+		})
+	}
+	return cases
+}
+
+// Maintainers decodes the maintainers file, or directory of team files, name of fsys, failing the test on error
+func Maintainers(t testing.TB, fsys fs.FS, name string) maintainers.Maintainers {
+	t.Helper()
+	ms, err := maintainers.DecodeFS(fsys, name)
+	if err != nil {
+		t.Fatalf("failed to decode maintainers file [%s]: %v", name, err)
+	}
+	return ms
+}
+
+// Index decodes the index file name of fsys, failing the test on error
+func Index(t testing.TB, fsys fs.FS, name string) *repo.IndexFile {
+	t.Helper()
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		t.Fatalf("failed to read index file [%s]: %v", name, err)
+	}
+	index := repo.NewIndexFile()
+	if err := sigyaml.UnmarshalStrict(data, index); err != nil {
+		t.Fatalf("failed to decode index file [%s]: %v", name, err)
+	}
+	index.SortEntries()
+	return index
+}
+
+// FormatResults renders results the way the golden files hold them, a line per result prefixed with its rule, e.g.
+// [unowned-chart] error: chart [fleet] is missing from maintainers file [maintainers.yaml]
+func FormatResults(results validate.Results) []byte {
+	var buf bytes.Buffer
+	for _, r := range results {
+		fmt.Fprintf(&buf, "[%s] %s\n", r.Rule, r)
+	}
+	return buf.Bytes()
+}
+
+// AssertGolden compares got to the golden file at path on disk, relative to the package of the test, or writes it
+// with -cowhandtest.update
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file [%s], run with -cowhandtest.update to create it: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("findings differ from golden file [%s]\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// AssertResults compares results to the golden findings of c
+func AssertResults(t testing.TB, c Case, results validate.Results) {
+	t.Helper()
+	if got := FormatResults(results); !bytes.Equal(got, c.Golden) {
+		t.Errorf("findings of case [%s] differ from its golden file\ngot:\n%s\nwant:\n%s", c.Name, got, c.Golden)
+	}
+}
+
+// RunCorpus runs validate.Run with opts on every case of the corpus as a subtest, comparing the findings to the golden
+// files. Options that change the findings, e.g. validate.WithRules, make the golden files of the corpus not apply
+func RunCorpus(t *testing.T, opts ...validate.Option) {
+	for _, c := range Cases(t) {
+		t.Run(c.Name, func(t *testing.T) {
+			results, err := validate.Run(context.Background(), c.Maintainers, c.Index, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			AssertResults(t, c, results)
+		})
+	}
+}
+
+// RunPlugin runs the plugin executable on the case, for plugin tests comparing the findings with AssertGolden
+func RunPlugin(t testing.TB, plugin string, c Case) validate.Results {
+	t.Helper()
+	return validate.RunPlugins(context.Background(), []string{plugin}, c.Maintainers, c.Index, "maintainers.yaml", "index.yaml")
+}
@@ -0,0 +1,155 @@
+package maintainers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Decode reads a maintainers file from r, decoding it as it is read
+func Decode(r io.Reader) (Maintainers, error) {
+	var ms Maintainers
+	// An empty file decodes to no teams, like yaml.Unmarshal of no data
+	if err := yaml.NewDecoder(r).Decode(&ms); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// DecodeFile reads the maintainers file at path, or every team file of path if it is a directory
+func DecodeFile(path string) (Maintainers, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return DecodeDir(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Decode(file)
+}
+
+// DecodeFS reads the maintainers file name of fsys, or every team file of name if it is a directory, e.g. of an
+// embed.FS or a fstest.MapFS
+func DecodeFS(fsys fs.FS, name string) (Maintainers, error) {
+	if info, err := fs.Stat(fsys, name); err == nil && info.IsDir() {
+		return DecodeDirFS(fsys, name)
+	}
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Decode(file)
+}
+
+// DecodeDir decodes the teams of every team file in dir, see TeamFiles, in the lexical order of the file names. A
+// team listed in two files is an error since it is no longer clear which file owns it
+func DecodeDir(dir string) (Maintainers, error) {
+	files, err := DecodeDirFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Merge(files)
+}
+
+// DecodeDirFS is DecodeDir for the directory dir of fsys
+func DecodeDirFS(fsys fs.FS, dir string) (Maintainers, error) {
+	files, err := decodeDir(fsys, dir, func(name string) string { return name })
+	if err != nil {
+		return nil, err
+	}
+	return Merge(files)
+}
+
+// File is a team file of a maintainers directory and the teams decoded from it
+type File struct {
+	Path  string
+	Teams Maintainers
+}
+
+// DecodeDirFiles is DecodeDir keeping the file every team was decoded from, so that what is found about a team can
+// be attributed to its file. The files are not merged, see Merge
+func DecodeDirFiles(dir string) ([]File, error) {
+	return decodeDir(os.DirFS(dir), ".", func(name string) string { return filepath.Join(dir, filepath.FromSlash(name)) })
+}
+
+// TeamFiles returns the team files of the maintainers directory dir sorted by name: its .yaml and .yml files, except
+// the hidden ones editors and tools leave behind
+func TeamFiles(dir string) ([]string, error) {
+	names, err := teamFiles(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, filepath.FromSlash(name))
+	}
+	return paths, nil
+}
+
+func teamFiles(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || (path.Ext(name) != ".yaml" && path.Ext(name) != ".yml") {
+			continue
+		}
+		names = append(names, path.Join(dir, name))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Merge concatenates the teams of the files in their order, failing if a team is in two files
+func Merge(files []File) (Maintainers, error) {
+	var ms Maintainers
+	owners := make(map[string]string)
+	for _, file := range files {
+		for _, m := range file.Teams {
+			if previous, ok := owners[m.Name]; ok {
+				return nil, fmt.Errorf("team [%s] is in both [%s] and [%s]", m.Name, previous, file.Path)
+			}
+			owners[m.Name] = file.Path
+		}
+		ms = append(ms, file.Teams...)
+	}
+	return ms, nil
+}
+
+// decodeDir decodes the team files of dir, naming them in errors with display
+func decodeDir(fsys fs.FS, dir string, display func(string) string) ([]File, error) {
+	names, err := teamFiles(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("maintainers directory [%s] has no .yaml or .yml files", display(dir))
+	}
+	files := make([]File, 0, len(names))
+	for _, name := range names {
+		file, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		teams, err := Decode(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode maintainers file [%s]: %w", display(name), err)
+		}
+		files = append(files, File{Path: display(name), Teams: teams})
+	}
+	return files, nil
+}
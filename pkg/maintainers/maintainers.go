@@ -0,0 +1,210 @@
+// Package maintainers is the model of the maintainers file: the teams maintaining the charts of a repository, their
+// contacts and the GitHub labels and issues of every chart
+package maintainers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+type Maintainers []*Maintainer
+type Maintainer struct {
+	Name    string  `yaml:"name" json:"name" schema:"required" description:"Name of the team, e.g. Neo Engineering Team (team/area1)"`
+	Contact Contact `yaml:"contact" json:"contact" description:"How to reach the team"`
+	Charts  []Chart `yaml:"charts" json:"charts" description:"Charts maintained by the team"`
+}
+
+type Contact struct {
+	Email        string `yaml:"email" json:"email" description:"Email of the team"`
+	SlackChannel string `yaml:"slackChannel,omitempty" json:"slackChannel,omitempty" description:"Slack channel of the team, e.g. #team-area1"`
+	URL          string `yaml:"url,omitempty" json:"url,omitempty" description:"URL of the page of the team"`
+}
+
+type Chart struct {
+	Name          string   `yaml:"name" json:"name" schema:"required" description:"Name of the chart in the index"`
+	GenerateIssue bool     `yaml:"generateIssue" json:"generateIssue" description:"Whether a tracking issue is created for the chart on every release"`
+	GithubLabels  []string `yaml:"githubLabels" json:"githubLabels" description:"Labels of the issues of the chart"`
+	Repositories  []string `yaml:"repositories,omitempty" json:"repositories,omitempty" description:"Chart repositories of the config file the chart belongs to, the default repository if empty"`
+	// AcknowledgedDependencies are the charts maintained by other teams the chart knowingly depends on
+	AcknowledgedDependencies []string `yaml:"acknowledgedDependencies,omitempty" json:"acknowledgedDependencies,omitempty" description:"Charts maintained by other teams the chart knowingly depends on"`
+	// MaintainedVersions optionally restricts ownership to a semver range of the chart, e.g. "104.x", so that
+	// several teams can each maintain a line of the same chart
+	MaintainedVersions string `yaml:"maintainedVersions,omitempty" json:"maintainedVersions,omitempty" description:"Semver range of the chart the team maintains, e.g. 104.x, all versions if empty"`
+}
+
+// FindChart returns the chart with the given name and the team maintaining it, or nils if no team maintains it
+func (ms Maintainers) FindChart(name string) (*Maintainer, *Chart) {
+	for _, m := range ms {
+		for i := range m.Charts {
+			if m.Charts[i].Name == name {
+				return m, &m.Charts[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// FindChartVersion is FindChart for a specific version, returning the first chart whose maintainedVersions
+// matches it
+func (ms Maintainers) FindChartVersion(name, version string) (*Maintainer, *Chart) {
+	for _, m := range ms {
+		for i := range m.Charts {
+			if m.Charts[i].Name == name && m.Charts[i].Maintains(version) {
+				return m, &m.Charts[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// FindTeam returns the team with the given name, or the only team whose name contains it ignoring case
+func (ms Maintainers) FindTeam(name string) (*Maintainer, error) {
+	var matches []*Maintainer
+	for _, m := range ms {
+		if m.Name == name {
+			return m, nil
+		}
+		if strings.Contains(strings.ToLower(m.Name), strings.ToLower(name)) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no team matches [%s]", name)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.Name)
+	}
+	return nil, fmt.Errorf("team [%s] is ambiguous, it matches [%s]", name, strings.Join(names, "], ["))
+}
+
+// FindTeamByName returns the team with exactly the given name and its position, or nil and -1
+func (ms Maintainers) FindTeamByName(name string) (*Maintainer, int) {
+	for i, m := range ms {
+		if m.Name == name {
+			return m, i
+		}
+	}
+	return nil, -1
+}
+
+// FindTeamChart returns the chart of the team with exactly the given name, or nil
+func (ms Maintainers) FindTeamChart(team, chart string) *Chart {
+	m, _ := ms.FindTeamByName(team)
+	if m == nil {
+		return nil
+	}
+	for i := range m.Charts {
+		if m.Charts[i].Name == chart {
+			return &m.Charts[i]
+		}
+	}
+	return nil
+}
+
+// WithContact returns the teams matching every field set in contact, emails ignore case and slack channels the
+// leading #
+func (ms Maintainers) WithContact(contact Contact) Maintainers {
+	normalizeSlack := func(channel string) string { return strings.TrimPrefix(strings.TrimSpace(channel), "#") }
+	var teams Maintainers
+	for _, m := range ms {
+		if contact.Email != "" && !strings.EqualFold(strings.TrimSpace(m.Contact.Email), strings.TrimSpace(contact.Email)) {
+			continue
+		}
+		if contact.SlackChannel != "" && normalizeSlack(m.Contact.SlackChannel) != normalizeSlack(contact.SlackChannel) {
+			continue
+		}
+		if contact.URL != "" && strings.TrimSuffix(m.Contact.URL, "/") != strings.TrimSuffix(contact.URL, "/") {
+			continue
+		}
+		teams = append(teams, m)
+	}
+	return teams
+}
+
+// InRepository returns the teams with only their charts that belong to the repository
+func (ms Maintainers) InRepository(name, defaultRepository string) Maintainers {
+	var filtered Maintainers
+	for _, m := range ms {
+		team := *m
+		team.Charts = nil
+		for _, chart := range m.Charts {
+			for _, repository := range chart.RepositoryNames(defaultRepository) {
+				if repository == name {
+					team.Charts = append(team.Charts, chart)
+					break
+				}
+			}
+		}
+		filtered = append(filtered, &team)
+	}
+	return filtered
+}
+
+// ChartNames returns the sorted, deduplicated names of every chart in the maintainers file
+func (ms Maintainers) ChartNames() []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, m := range ms {
+		for _, chart := range m.Charts {
+			if _, ok := seen[chart.Name]; !ok {
+				seen[chart.Name] = struct{}{}
+				names = append(names, chart.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Maintains reports whether version is in the chart's maintainedVersions, every version is if it has none and
+// none are if the range or version are invalid
+func (c Chart) Maintains(version string) bool {
+	if c.MaintainedVersions == "" {
+		return true
+	}
+	constraint, err := semver.NewConstraint(c.MaintainedVersions)
+	if err != nil {
+		return false
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return constraint.Check(v)
+}
+
+// Acknowledges reports whether the chart lists dependency in its acknowledgedDependencies
+func (c Chart) Acknowledges(dependency string) bool {
+	for _, name := range c.AcknowledgedDependencies {
+		if name == dependency {
+			return true
+		}
+	}
+	return false
+}
+
+// RepositoryNames returns the repositories the chart belongs to, defaultRepository if it does not declare any
+func (c Chart) RepositoryNames(defaultRepository string) []string {
+	if len(c.Repositories) == 0 {
+		return []string{defaultRepository}
+	}
+	return c.Repositories
+}
+
+// String joins the contact methods that are set, e.g. "team@example.com, #team-slack"
+func (c Contact) String() string {
+	var parts []string
+	for _, value := range []string{c.Email, c.SlackChannel, c.URL} {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
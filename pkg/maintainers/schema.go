@@ -0,0 +1,91 @@
+package maintainers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//go:generate go run ../.. schema -o maintainers.schema.json
+
+// Schema is the JSON Schema of the maintainers file, editors use it to complete and check maintainers.yaml. It is
+// GenerateSchema at the time of go generate, so it has to be regenerated whenever the types change
+//
+//go:embed maintainers.schema.json
+var Schema []byte
+
+// GenerateSchema derives the JSON Schema of the maintainers file from the types: every struct is a definition named
+// after it, fields are named by their json tag, described by their description tag and, with a schema:"required"
+// tag, required and not empty
+func GenerateSchema() ([]byte, error) {
+	defs := make(map[string]interface{})
+	schema := map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "maintainers.yaml",
+		"description": "The teams maintaining the charts of a repository, their contacts and the GitHub labels and issues of every chart",
+		"type":        "array",
+		"items":       typeSchema(reflect.TypeOf(Maintainer{}), defs),
+		"$defs":       defs,
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// typeSchema returns the schema of t, adding the definitions of the structs it uses to defs
+func typeSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), defs)
+	case reflect.Slice:
+		// An empty list in YAML, e.g. githubLabels: with nothing after it, decodes as null
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": typeSchema(t.Elem(), defs)}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Struct:
+		name := strings.ToLower(t.Name())
+		if _, ok := defs[name]; !ok {
+			defs[name] = nil
+			defs[name] = structSchema(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+func structSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		property := typeSchema(field.Type, defs)
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		if field.Tag.Get("schema") == "required" {
+			required = append(required, name)
+			if field.Type.Kind() == reflect.String {
+				property["minLength"] = 1
+			}
+		}
+		properties[name] = property
+	}
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,54 @@
+package validate
+
+import "errors"
+
+// The errors a Result wraps depending on its rule, so callers can tell them apart with errors.Is, e.g.
+// errors.Is(results.Err(), validate.ErrUnownedChart)
+var (
+	ErrInvalidMaintainedVersions = errors.New("invalid maintainedVersions")
+	ErrCRDGenerateIssue          = errors.New("crd chart with generateIssue")
+	ErrDuplicateLabel            = errors.New("duplicate label")
+	ErrDuplicateChart            = errors.New("duplicate chart")
+	ErrEmptyIndex                = errors.New("empty index")
+	ErrUnownedChart              = errors.New("unowned chart")
+	ErrMissingFromIndex          = errors.New("chart missing from index")
+	ErrTeamAnnotation            = errors.New("maintainer team annotation mismatch")
+	ErrOverlappingVersions       = errors.New("overlapping maintainedVersions")
+	ErrUnmatchedVersions         = errors.New("maintainedVersions matching no version")
+	ErrSchema                    = errors.New("maintainers file not matching the schema")
+)
+
+var ruleErrors = map[string]error{
+	RuleMaintainedVersions:  ErrInvalidMaintainedVersions,
+	RuleCRDGenerateIssue:    ErrCRDGenerateIssue,
+	RuleDuplicateLabel:      ErrDuplicateLabel,
+	RuleDuplicateChart:      ErrDuplicateChart,
+	RuleEmptyIndex:          ErrEmptyIndex,
+	RuleUnownedChart:        ErrUnownedChart,
+	RuleMissingFromIndex:    ErrMissingFromIndex,
+	RuleTeamAnnotation:      ErrTeamAnnotation,
+	RuleOverlappingVersions: ErrOverlappingVersions,
+	RuleUnmatchedVersions:   ErrUnmatchedVersions,
+	RuleSchema:              ErrSchema,
+}
+
+// Error makes a Result an error, errors.As finds it in the error of Results.Err
+func (r Result) Error() string {
+	return r.String()
+}
+
+// Unwrap returns the error of the result's rule, or nil for a rule this package does not define
+func (r Result) Unwrap() error {
+	return ruleErrors[r.Rule]
+}
+
+// Err joins the errors of the results, warnings are left out, or returns nil if there are none
+func (rs Results) Err() error {
+	var errs []error
+	for _, r := range rs {
+		if r.Severity == SeverityError {
+			errs = append(errs, r)
+		}
+	}
+	return errors.Join(errs...)
+}
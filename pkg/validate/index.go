@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"sort"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+// MaintainerTeamAnnotation names the team maintaining a chart in its Chart.yaml and index entries
+const MaintainerTeamAnnotation = "catalog.cattle.io/maintainer-team"
+
+// IndexChartNames returns the sorted names of every chart in the index
+func IndexChartNames(index *repo.IndexFile) []string {
+	names := make([]string, 0, len(index.Entries))
+	for name := range index.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckMaintainerTeamAnnotation returns a result if annotations name another team than the one maintaining the
+// chart version, or nil if they match, the annotation is missing or no team maintains the chart
+func CheckMaintainerTeamAnnotation(ms maintainers.Maintainers, chartName, version string, annotations map[string]string, source string) *Result {
+	annotated, ok := annotations[MaintainerTeamAnnotation]
+	if !ok {
+		return nil
+	}
+	m, _ := ms.FindChartVersion(chartName, version)
+	if m == nil || m.Name == annotated {
+		return nil
+	}
+	result := errorf(RuleTeamAnnotation, chartName, "chart [%s] version [%s] has annotation [%s: %s] in [%s] but is maintained by [%s]", chartName, version, MaintainerTeamAnnotation, annotated, source, m.Name)
+	return &result
+}
+
+// OrphanCharts returns the sorted names of the charts of the index no team maintains under either of their aliases
+func OrphanCharts(aliases map[string]string, ms maintainers.Maintainers, index *repo.IndexFile) []string {
+	maintained := make(map[string]struct{})
+	for _, chartName := range ms.ChartNames() {
+		maintained[CanonicalChartName(aliases, chartName)] = struct{}{}
+	}
+	var orphans []string
+	for _, chartName := range IndexChartNames(index) {
+		if _, ok := maintained[CanonicalChartName(aliases, chartName)]; !ok {
+			orphans = append(orphans, chartName)
+		}
+	}
+	return orphans
+}
+
+// CanonicalChartName returns the name a chart was renamed to in aliases, or name if it was not renamed
+func CanonicalChartName(aliases map[string]string, name string) string {
+	if renamed, ok := aliases[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// AliasedChartVersions returns the versions of a chart in the index under its name and every name aliased to it
+func AliasedChartVersions(index *repo.IndexFile, aliases map[string]string, chartName string) repo.ChartVersions {
+	canonical := CanonicalChartName(aliases, chartName)
+	var versions repo.ChartVersions
+	for _, name := range IndexChartNames(index) {
+		if CanonicalChartName(aliases, name) == canonical {
+			versions = append(versions, index.Entries[name]...)
+		}
+	}
+	return versions
+}
+
+// LatestChartVersion returns the latest stable version of a chart in the index, or nil if it has none
+func LatestChartVersion(index *repo.IndexFile, chartName string) *repo.ChartVersion {
+	cv, err := index.Get(chartName, "")
+	if err != nil {
+		return nil
+	}
+	return cv
+}
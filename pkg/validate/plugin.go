@@ -0,0 +1,108 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+// PluginInput is what a plugin receives as JSON on stdin, Index is null when the maintainers file is validated
+// without one. Plugins written in Go can decode it as is
+type PluginInput struct {
+	MaintainersFile string                  `json:"maintainersFile"`
+	IndexFile       string                  `json:"indexFile,omitempty"`
+	Maintainers     maintainers.Maintainers `json:"maintainers"`
+	Index           *repo.IndexFile         `json:"index"`
+}
+
+// RunPlugins runs every plugin executable on the maintainers and index and returns their findings as results. A
+// plugin prints a JSON array of results on stdout, e.g. [{"chart": "fleet", "message": "..."}], where the rule
+// defaults to the name of the executable and the severity to error. A plugin that fails is reported as an error
+// instead of stopping the others
+func RunPlugins(ctx context.Context, plugins []string, ms maintainers.Maintainers, index *repo.IndexFile, maintainersFilePath, indexFilePath string) Results {
+	if len(plugins) == 0 {
+		return nil
+	}
+	input, err := json.Marshal(PluginInput{MaintainersFile: maintainersFilePath, IndexFile: indexFilePath, Maintainers: ms, Index: index})
+	if err != nil {
+		return Results{pluginError("", "failed to encode the plugin input: %v", err)}
+	}
+	var results Results
+	for _, plugin := range plugins {
+		pctx, span := tracer.Start(ctx, "plugin", trace.WithAttributes(attribute.String("cowhand.plugin", plugin)))
+		found, err := runPlugin(pctx, plugin, input)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+			results = append(results, pluginError(plugin, "%v", err))
+			continue
+		}
+		span.SetAttributes(attribute.Int("cowhand.results", len(found)))
+		span.End()
+		results = append(results, found...)
+	}
+	return results
+}
+
+func runPlugin(ctx context.Context, plugin string, input []byte) (Results, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin [%s] failed: %s", plugin, msg)
+		}
+		return nil, fmt.Errorf("plugin [%s] failed: %w", plugin, err)
+	}
+	var results Results
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		return nil, fmt.Errorf("plugin [%s] printed invalid results, expected a JSON array of results: %w", plugin, err)
+	}
+	for i := range results {
+		if results[i].Rule == "" {
+			results[i].Rule = pluginRule(plugin)
+		}
+		switch results[i].Severity {
+		case "":
+			results[i].Severity = SeverityError
+		case SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("plugin [%s] reported unknown severity [%s], use error or warning", plugin, results[i].Severity)
+		}
+		if results[i].Message == "" {
+			return nil, fmt.Errorf("plugin [%s] reported a result without a message", plugin)
+		}
+	}
+	return results, nil
+}
+
+// pluginRule is the rule of the results of a plugin that do not name one, e.g. my-rule for ./plugins/my-rule.sh
+func pluginRule(plugin string) string {
+	base := filepath.Base(plugin)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func pluginError(plugin, format string, args ...interface{}) Result {
+	rule := "plugin"
+	if plugin != "" {
+		rule = pluginRule(plugin)
+	}
+	return Result{Rule: rule, Severity: SeverityError, Message: fmt.Sprintf(format, args...)}
+}
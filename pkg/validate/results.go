@@ -0,0 +1,83 @@
+package validate
+
+import "fmt"
+
+// Severity is how serious a result is, errors fail a validation and warnings do not
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// The rules a Result can come from, Run enables all of them unless told otherwise. They are also the stable codes
+// of the results, each wrapping the matching error of errors.go
+const (
+	RuleMaintainedVersions  = "maintained-versions"
+	RuleCRDGenerateIssue    = "crd-generate-issue"
+	RuleDuplicateLabel      = "duplicate-label"
+	RuleDuplicateChart      = "duplicate-chart"
+	RuleEmptyIndex          = "empty-index"
+	RuleUnownedChart        = "unowned-chart"
+	RuleMissingFromIndex    = "missing-from-index"
+	RuleTeamAnnotation      = "team-annotation"
+	RuleOverlappingVersions = "overlapping-versions"
+	RuleUnmatchedVersions   = "unmatched-versions"
+)
+
+// RuleSchema is the rule of the results of Schema, which checks the file itself and is not run by Run
+const RuleSchema = "schema"
+
+// Rules lists every rule in the order Run runs them
+var Rules = []string{
+	RuleMaintainedVersions,
+	RuleCRDGenerateIssue,
+	RuleDuplicateLabel,
+	RuleDuplicateChart,
+	RuleEmptyIndex,
+	RuleUnownedChart,
+	RuleMissingFromIndex,
+	RuleTeamAnnotation,
+	RuleOverlappingVersions,
+	RuleUnmatchedVersions,
+}
+
+// Result is one problem found by a rule. Chart is the chart it is about, "" for problems with the whole file, and
+// Message describes it without the severity
+type Result struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Chart    string   `json:"chart,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String renders the result the way cowhand prints it, e.g. "error: chart [fleet] has duplicate label [team/a]"
+func (r Result) String() string {
+	return fmt.Sprintf("%s: %s", r.Severity, r.Message)
+}
+
+// Results are the results of a validation in the order the rules found them
+type Results []Result
+
+// HasErrors reports whether any result is an error
+func (rs Results) HasErrors() bool {
+	for _, r := range rs {
+		if r.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Strings renders every result with String
+func (rs Results) Strings() []string {
+	lines := make([]string, 0, len(rs))
+	for _, r := range rs {
+		lines = append(lines, r.String())
+	}
+	return lines
+}
+
+func errorf(rule, chart, format string, args ...interface{}) Result {
+	return Result{Rule: rule, Severity: SeverityError, Chart: chart, Message: fmt.Sprintf(format, args...)}
+}
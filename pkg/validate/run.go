@@ -0,0 +1,189 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+// tracer records the spans of Run with the global tracer provider, a no-op unless the program sets one
+var tracer = otel.Tracer("github.com/pennyscissors/go-playground/pkg/validate")
+
+// Option configures Run
+type Option func(*options)
+
+type options struct {
+	enabled             map[string]bool
+	severities          map[string]Severity
+	ignoredCharts       []string
+	aliases             map[string]string
+	suggestions         []Suggestion
+	defaultRepository   string
+	maintainersFilePath string
+	indexFilePath       string
+	logger              *slog.Logger
+	errs                []error
+}
+
+// WithRules only runs the given rules, every rule runs by default
+func WithRules(rules ...string) Option {
+	return func(o *options) {
+		o.enabled = make(map[string]bool)
+		for _, rule := range rules {
+			o.checkRule(rule)
+			o.enabled[rule] = true
+		}
+	}
+}
+
+// WithoutRules skips the given rules
+func WithoutRules(rules ...string) Option {
+	return func(o *options) {
+		if o.enabled == nil {
+			o.enabled = make(map[string]bool)
+			for _, rule := range Rules {
+				o.enabled[rule] = true
+			}
+		}
+		for _, rule := range rules {
+			o.checkRule(rule)
+			delete(o.enabled, rule)
+		}
+	}
+}
+
+// WithSeverity reports the results of rule with severity instead of its own, e.g. to only warn about unowned charts
+func WithSeverity(rule string, severity Severity) Option {
+	return func(o *options) {
+		o.checkRule(rule)
+		if severity != SeverityError && severity != SeverityWarning {
+			o.errs = append(o.errs, fmt.Errorf("unknown severity [%s] for rule [%s], use error or warning", severity, rule))
+		}
+		o.severities[rule] = severity
+	}
+}
+
+// WithIgnoredCharts drops the results about the charts whose name matches one of the path.Match patterns, e.g.
+// rancher-*-crd
+func WithIgnoredCharts(patterns ...string) Option {
+	return func(o *options) {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				o.errs = append(o.errs, fmt.Errorf("invalid ignored chart pattern [%s]: %w", pattern, err))
+			}
+		}
+		o.ignoredCharts = append(o.ignoredCharts, patterns...)
+	}
+}
+
+// WithAliases treats the charts renamed from the keys of aliases to their values as the same chart
+func WithAliases(aliases map[string]string) Option {
+	return func(o *options) { o.aliases = aliases }
+}
+
+// WithSuggestions suggests a team for the unowned charts from the suggestions before guessing one
+func WithSuggestions(suggestions []Suggestion) Option {
+	return func(o *options) { o.suggestions = suggestions }
+}
+
+// WithDefaultRepository is the repository of the charts that do not declare any, for the duplicate chart rule
+func WithDefaultRepository(name string) Option {
+	return func(o *options) { o.defaultRepository = name }
+}
+
+// WithSources names the maintainers and index files in the messages, maintainers.yaml and index.yaml by default
+func WithSources(maintainersFilePath, indexFilePath string) Option {
+	return func(o *options) {
+		o.maintainersFilePath = maintainersFilePath
+		o.indexFilePath = indexFilePath
+	}
+}
+
+// WithLogHandler sends the diagnostics of Run, e.g. which results were dropped and why, to h at the debug level, they
+// are discarded by default
+func WithLogHandler(h slog.Handler) Option {
+	return func(o *options) { o.logger = slog.New(h) }
+}
+
+func (o *options) checkRule(rule string) {
+	for _, known := range Rules {
+		if rule == known {
+			return
+		}
+	}
+	o.errs = append(o.errs, fmt.Errorf("unknown rule [%s]", rule))
+}
+
+// Run validates the maintainers file on its own and, unless index is nil, against the index. The error is only set
+// for invalid options or when ctx is done, problems found in the files are results
+func Run(ctx context.Context, ms maintainers.Maintainers, index *repo.IndexFile, opts ...Option) (Results, error) {
+	o := &options{
+		severities:          make(map[string]Severity),
+		maintainersFilePath: "maintainers.yaml",
+		indexFilePath:       "index.yaml",
+		logger:              slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.errs) > 0 {
+		return nil, o.errs[0]
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ctx, span := tracer.Start(ctx, "validate", trace.WithAttributes(attribute.String("cowhand.maintainers_file", o.maintainersFilePath)))
+	defer span.End()
+	o.logger.DebugContext(ctx, "validating maintainers file", "source", o.maintainersFilePath, "teams", len(ms))
+	_, lint := tracer.Start(ctx, "lint", trace.WithAttributes(attribute.Int("cowhand.teams", len(ms))))
+	results := Lint(ms, o.defaultRepository)
+	lint.SetAttributes(attribute.Int("cowhand.results", len(results)))
+	lint.End()
+	if index != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		o.logger.DebugContext(ctx, "cross-checking index", "source", o.indexFilePath, "charts", len(index.Entries))
+		_, cross := tracer.Start(ctx, "cross-check index", trace.WithAttributes(attribute.String("cowhand.index", o.indexFilePath), attribute.Int("cowhand.charts", len(index.Entries))))
+		crossResults := CrossCheckIndex(o.aliases, o.suggestions, ms, index, "", o.maintainersFilePath, o.indexFilePath)
+		cross.SetAttributes(attribute.Int("cowhand.results", len(crossResults)))
+		cross.End()
+		results = append(results, crossResults...)
+	}
+	kept := results[:0]
+	for _, r := range results {
+		if o.enabled != nil && !o.enabled[r.Rule] {
+			o.logger.DebugContext(ctx, "dropping result of disabled rule", "rule", r.Rule, "chart", r.Chart)
+			continue
+		}
+		if o.ignored(r.Chart) {
+			o.logger.DebugContext(ctx, "dropping result of ignored chart", "rule", r.Rule, "chart", r.Chart)
+			continue
+		}
+		if severity, ok := o.severities[r.Rule]; ok && severity != r.Severity {
+			o.logger.DebugContext(ctx, "overriding severity", "rule", r.Rule, "chart", r.Chart, "from", r.Severity, "to", severity)
+			r.Severity = severity
+		}
+		kept = append(kept, r)
+	}
+	span.SetAttributes(attribute.Int("cowhand.results", len(kept)), attribute.Int("cowhand.dropped", len(results)-len(kept)))
+	o.logger.DebugContext(ctx, "validated maintainers file", "source", o.maintainersFilePath, "results", len(kept), "dropped", len(results)-len(kept))
+	return kept, nil
+}
+
+func (o *options) ignored(chart string) bool {
+	for _, pattern := range o.ignoredCharts {
+		if ok, _ := path.Match(pattern, chart); ok && chart != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package validate_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pennyscissors/go-playground/pkg/cowhandtest"
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func TestRunCorpus(t *testing.T) {
+	cowhandtest.RunCorpus(t)
+}
+
+func TestRunOptions(t *testing.T) {
+	var unowned cowhandtest.Case
+	for _, c := range cowhandtest.Cases(t) {
+		if c.Name == "unowned" {
+			unowned = c
+		}
+	}
+	tests := []struct {
+		name string
+		opts []validate.Option
+	}{
+		{name: "aliases", opts: []validate.Option{validate.WithAliases(map[string]string{"rancher-retired": "fleet"})}},
+		{name: "ignored-charts", opts: []validate.Option{validate.WithIgnoredCharts("flee*")}},
+		{name: "severity", opts: []validate.Option{validate.WithSeverity(validate.RuleUnownedChart, validate.SeverityWarning)}},
+		{name: "rules", opts: []validate.Option{validate.WithRules(validate.RuleTeamAnnotation)}},
+		{name: "sources", opts: []validate.Option{validate.WithSources("charts/maintainers.yaml", "https://charts.example.com/index.yaml")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := validate.Run(context.Background(), unowned.Maintainers, unowned.Index, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cowhandtest.AssertGolden(t, filepath.Join("testdata", tt.name+".golden"), cowhandtest.FormatResults(results))
+		})
+	}
+}
+
+func TestRunInvalidOptions(t *testing.T) {
+	c := cowhandtest.Cases(t)[0]
+	for name, opt := range map[string]validate.Option{
+		"unknown rule":     validate.WithRules("no-such-rule"),
+		"unknown severity": validate.WithSeverity(validate.RuleUnownedChart, "critical"),
+		"invalid pattern":  validate.WithIgnoredCharts("["),
+	} {
+		if _, err := validate.Run(context.Background(), c.Maintainers, c.Index, opt); err == nil {
+			t.Errorf("%s: Run did not fail", name)
+		}
+	}
+}
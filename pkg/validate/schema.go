@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	sigyaml "sigs.k8s.io/yaml"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+const schemaURL = "maintainers.schema.json"
+
+// Schema validates the maintainers file data against maintainers.Schema, returning a result for every violation
+// with the path of the offending value, e.g. /2/charts/0. Source names the file in the messages. The error is only
+// set when data is not YAML at all
+func Schema(data []byte, source string) (Results, error) {
+	schema, err := jsonschema.UnmarshalJSON(bytes.NewReader(maintainers.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the maintainers schema: %w", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, schema); err != nil {
+		return nil, fmt.Errorf("failed to load the maintainers schema: %w", err)
+	}
+	validator, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile the maintainers schema: %w", err)
+	}
+	// The YAML goes through JSON so that the numbers and keys are the types the validator expects
+	data, err = sigyaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode maintainers file [%s]: %w", source, err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode maintainers file [%s]: %w", source, err)
+	}
+	var invalid *jsonschema.ValidationError
+	if err := validator.Validate(instance); !errors.As(err, &invalid) {
+		return nil, err
+	}
+	var results Results
+	printer := message.NewPrinter(language.English)
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+		location := "/" + strings.Join(e.InstanceLocation, "/")
+		results = append(results, errorf(RuleSchema, "", "maintainers file [%s] does not match the schema at [%s]: %s", source, location, e.ErrorKind.LocalizedString(printer)))
+	}
+	walk(invalid)
+	return results, nil
+}
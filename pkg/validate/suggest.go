@@ -0,0 +1,52 @@
+package validate
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+// Suggestion maps chart names matching a path.Match pattern, e.g. "rancher-monitoring*", to the team that most
+// likely owns them
+type Suggestion struct {
+	Pattern string `yaml:"pattern"`
+	Team    string `yaml:"team"`
+}
+
+// SuggestTeam guesses the team owning a chart missing from the maintainers file, or returns "" without a good guess.
+// The first matching suggestion wins, otherwise the chart goes to the team owning its closest sibling:
+// the chart it is the crd of or that its name extends, e.g. fleet for fleet-agent, or else the charts sharing the
+// longest prefix of at least two dash separated words, as long as they are all owned by the same team
+func SuggestTeam(suggestions []Suggestion, ms maintainers.Maintainers, chartName string) string {
+	for _, s := range suggestions {
+		if ok, _ := path.Match(s.Pattern, chartName); ok {
+			return s.Team
+		}
+	}
+	words := strings.Split(strings.TrimSuffix(chartName, "-crd"), "-")
+	best, bestTeam, tied := 0, "", false
+	for _, m := range ms {
+		for _, chart := range m.Charts {
+			other := strings.Split(chart.Name, "-")
+			n := 0
+			for n < len(words) && n < len(other) && words[n] == other[n] {
+				n++
+			}
+			// Names extending a maintained chart are siblings regardless of how short the shared prefix is
+			if n < 2 && n != len(other) {
+				continue
+			}
+			switch {
+			case n > best:
+				best, bestTeam, tied = n, m.Name, false
+			case n == best && m.Name != bestTeam:
+				tied = true
+			}
+		}
+	}
+	if tied {
+		return ""
+	}
+	return bestTeam
+}
@@ -0,0 +1,132 @@
+// Package validate checks a maintainers file on its own and against the index of the chart repository it describes,
+// returning every problem found as a Result naming the rule that found it
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+// Lint returns the problems found in the maintainers file alone, a chart may only be listed once per repository,
+// charts without repositories belong to defaultRepository
+func Lint(ms maintainers.Maintainers, defaultRepository string) Results {
+	var results Results
+	// Build map of charts from maintainers file and validate it there are no chart or label duplicates
+	maintainersCharts := make(map[string]struct{})
+	duplicateCharts := make(map[string]struct{})
+	unrangedCharts := make(map[string]struct{})
+	for _, m := range ms {
+		for _, chart := range m.Charts {
+			if chart.MaintainedVersions != "" {
+				if _, err := semver.NewConstraint(chart.MaintainedVersions); err != nil {
+					results = append(results, errorf(RuleMaintainedVersions, chart.Name, "chart [%s] has invalid maintainedVersions [%s]: %v", chart.Name, chart.MaintainedVersions, err))
+				}
+			}
+			// Validate crd charts do not have generateIssue == true since we don't track crd charts on issues separately
+			if strings.HasSuffix(chart.Name, "-crd") && chart.GenerateIssue {
+				results = append(results, errorf(RuleCRDGenerateIssue, chart.Name, "crd chart [%s] has field [generateIssue: %t] which is incorrect as crd charts are not tracked in issues separately", chart.Name, chart.GenerateIssue))
+			}
+			// Validate each chart does not have any GitHub label duplicates
+			duplicateLabels := make(map[string]struct{})
+			for _, label := range chart.GithubLabels {
+				if _, ok := duplicateLabels[label]; ok {
+					results = append(results, errorf(RuleDuplicateLabel, chart.Name, "chart [%s] has duplicate label [%s]", chart.Name, label))
+				}
+				duplicateLabels[label] = struct{}{}
+			}
+			// Validate maintainers do not have any chart duplicates in their team or accross teams, unless every
+			// entry of the chart only maintains a range of its versions
+			for _, repository := range chart.RepositoryNames(defaultRepository) {
+				key := repository + "/" + chart.Name
+				_, unranged := unrangedCharts[key]
+				if _, ok := maintainersCharts[key]; ok && (unranged || chart.MaintainedVersions == "") {
+					if _, ok := duplicateCharts[key]; !ok {
+						results = append(results, errorf(RuleDuplicateChart, chart.Name, "chart [%s] is a duplicate or wrongly set as maintained by more than one team", chart.Name))
+						duplicateCharts[key] = struct{}{}
+					}
+				}
+				maintainersCharts[key] = struct{}{}
+				if chart.MaintainedVersions == "" {
+					unrangedCharts[key] = struct{}{}
+				}
+			}
+		}
+	}
+	return results
+}
+
+// CrossCheckIndex returns a result for every chart of the index missing from the maintainers and vice versa,
+// repository names the repository the index belongs to in the messages if it is set. Charts renamed in aliases are
+// the same chart under either name, a team is suggested for the missing charts from suggestions
+func CrossCheckIndex(aliases map[string]string, suggestions []Suggestion, ms maintainers.Maintainers, index *repo.IndexFile, repository, maintainersFilePath, indexFilePath string) Results {
+	var results Results
+	if len(index.Entries) == 0 {
+		results = append(results, errorf(RuleEmptyIndex, "", "index file [%s] has no chart entries", indexFilePath))
+	}
+	indexCharts := make(map[string]struct{})
+	for _, chartName := range IndexChartNames(index) {
+		indexCharts[CanonicalChartName(aliases, chartName)] = struct{}{}
+	}
+	// Validate all charts in the index file exist in the maintainers file
+	for _, chartName := range OrphanCharts(aliases, ms, index) {
+		result := errorf(RuleUnownedChart, chartName, "chart [%s] is missing from maintainers file [%s]", chartName, maintainersFilePath)
+		if repository != "" {
+			result.Message = fmt.Sprintf("chart [%s] of repository [%s] is missing from maintainers file [%s]", chartName, repository, maintainersFilePath)
+		}
+		if team := SuggestTeam(suggestions, ms, chartName); team != "" {
+			result.Message += fmt.Sprintf(", suggested team [%s]", team)
+		}
+		results = append(results, result)
+	}
+	// Validate all charts in the maintainers file exist in the index file
+	for _, chartName := range ms.ChartNames() {
+		if _, ok := indexCharts[CanonicalChartName(aliases, chartName)]; !ok {
+			results = append(results, errorf(RuleMissingFromIndex, chartName, "chart [%s] does not exist in index file [%s]", chartName, indexFilePath))
+		}
+	}
+	// Validate the team annotated on the latest version of each chart is the team maintaining it
+	for _, chartName := range IndexChartNames(index) {
+		if cv := LatestChartVersion(index, chartName); cv != nil {
+			if result := CheckMaintainerTeamAnnotation(ms, CanonicalChartName(aliases, chartName), cv.Version, cv.Annotations, indexFilePath); result != nil {
+				results = append(results, *result)
+			}
+		}
+	}
+	// Validate version ranges match versions of the index and no version is maintained by more than one team
+	overlapping := make(map[string]struct{})
+	for _, m := range ms {
+		for i := range m.Charts {
+			chart := &m.Charts[i]
+			versions := AliasedChartVersions(index, aliases, chart.Name)
+			if chart.MaintainedVersions == "" || len(versions) == 0 {
+				continue
+			}
+			if _, err := semver.NewConstraint(chart.MaintainedVersions); err != nil {
+				continue
+			}
+			matched := false
+			for _, cv := range versions {
+				if !chart.Maintains(cv.Version) {
+					continue
+				}
+				matched = true
+				if _, ok := overlapping[chart.Name]; ok {
+					continue
+				}
+				if owner, first := ms.FindChartVersion(chart.Name, cv.Version); first != chart {
+					results = append(results, errorf(RuleOverlappingVersions, chart.Name, "version [%s] of chart [%s] is matched by maintainedVersions [%s] of [%s] and [%s] of [%s]", cv.Version, chart.Name, first.MaintainedVersions, owner.Name, chart.MaintainedVersions, m.Name))
+					overlapping[chart.Name] = struct{}{}
+				}
+			}
+			if !matched {
+				results = append(results, errorf(RuleUnmatchedVersions, chart.Name, "chart [%s] has maintainedVersions [%s] which matches no version in index file [%s]", chart.Name, chart.MaintainedVersions, indexFilePath))
+			}
+		}
+	}
+	return results
+}
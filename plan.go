@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type planAction string
+
+const (
+	planCreate planAction = "create"
+	planUpdate planAction = "update"
+	planSkip   planAction = "skip"
+)
+
+var planSymbols = map[planAction]string{
+	planCreate: "+",
+	planUpdate: "~",
+	planSkip:   "=",
+}
+
+// planChange is a single mutation; nothing touches the remote until apply is called on its plan
+type planChange struct {
+	action      planAction
+	kind        string
+	description string
+	apply       func() error
+}
+
+// plan collects every mutation a command intends to make so it can be previewed before it is executed
+type plan struct {
+	changes []*planChange
+}
+
+func (p *plan) add(action planAction, kind, description string, apply func() error) {
+	p.changes = append(p.changes, &planChange{
+		action:      action,
+		kind:        kind,
+		description: description,
+		apply:       apply,
+	})
+}
+
+func (p *plan) empty() bool {
+	for _, c := range p.changes {
+		if c.action != planSkip {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *plan) print(w io.Writer) {
+	for _, c := range p.changes {
+		if c.action == planSkip {
+			continue
+		}
+		fmt.Fprintf(w, "  %s %s %s %s\n", planSymbols[c.action], c.action, c.kind, c.description)
+	}
+	fmt.Fprintf(w, "\nPlan: %s\n", p.summary())
+}
+
+// summary renders the plan like "will create 14 issues, update 3 labels, skip 120 existing"
+func (p *plan) summary() string {
+	counts := make(map[planAction]map[string]int)
+	var kinds []string
+	seenKinds := make(map[string]struct{})
+	for _, c := range p.changes {
+		if counts[c.action] == nil {
+			counts[c.action] = make(map[string]int)
+		}
+		counts[c.action][c.kind]++
+		if _, ok := seenKinds[c.kind]; !ok {
+			seenKinds[c.kind] = struct{}{}
+			kinds = append(kinds, c.kind)
+		}
+	}
+	var parts []string
+	for _, action := range []planAction{planCreate, planUpdate} {
+		var items []string
+		for _, kind := range kinds {
+			if n := counts[action][kind]; n > 0 {
+				items = append(items, pluralize(n, kind))
+			}
+		}
+		if len(items) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", action, strings.Join(items, " and ")))
+		}
+	}
+	skipped := 0
+	for _, n := range counts[planSkip] {
+		skipped += n
+	}
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("skip %d existing", skipped))
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return "will " + strings.Join(parts, ", ")
+}
+
+// apply executes the plan in order, stopping at the first failure
+func (p *plan) apply(w io.Writer) error {
+	for _, c := range p.changes {
+		if c.action == planSkip {
+			continue
+		}
+		if err := c.apply(); err != nil {
+			return fmt.Errorf("failed to %s %s %s: %w", c.action, c.kind, c.description, err)
+		}
+		fmt.Fprintf(w, "%sd %s %s\n", c.action, c.kind, c.description)
+	}
+	return nil
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
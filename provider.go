@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// provider abstracts the issue, label and pull request operations of a code host. Repositories are
+// always addressed as owner/name (or group/subgroup/name on GitLab) and pull requests by their number
+type provider interface {
+	listLabels(ctx context.Context, repo string) ([]remoteLabel, error)
+	createLabel(ctx context.Context, repo string, label remoteLabel) error
+	// searchIssues returns issues in any state whose title contains text
+	searchIssues(ctx context.Context, repo, text string) ([]remoteIssue, error)
+	createIssue(ctx context.Context, repo, title, body string, labels []string) (*remoteIssue, error)
+	addIssueLabels(ctx context.Context, repo string, number int, labels []string) error
+	getPullRequest(ctx context.Context, repo string, number int) (*remoteIssue, error)
+	listPullRequestFiles(ctx context.Context, repo string, number int) ([]string, error)
+	addPullRequestLabels(ctx context.Context, repo string, number int, labels []string) error
+}
+
+type remoteLabel struct {
+	Name        string
+	Color       string
+	Description string
+}
+
+type remoteIssue struct {
+	Number int
+	Title  string
+	URL    string
+	Labels []string
+}
+
+// cacheFlags configure the on-disk cache of API reads
+type cacheFlags struct {
+	cacheDir string
+	cacheTTL time.Duration
+}
+
+func (f *cacheFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.cacheDir, "cache-dir", defaultCacheDir(), "directory used to cache API reads, empty disables caching")
+	fs.DurationVar(&f.cacheTTL, "cache-ttl", time.Hour, "how long cached API reads are used before being revalidated")
+}
+
+func (f *cacheFlags) transport() http.RoundTripper {
+	return newCachingTransport(f.cacheDir, f.cacheTTL)
+}
+
+// providerFlags are shared by every command that talks to a code host
+type providerFlags struct {
+	name    string
+	baseURL string
+	cacheFlags
+}
+
+func (f *providerFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.name, "provider", "github", "code host to talk to, one of github, gitlab or gitea")
+	fs.StringVar(&f.baseURL, "base-url", "", "API URL of the code host, defaults to the public github.com or gitlab.com API")
+	f.cacheFlags.register(fs)
+}
+
+// provider builds the selected provider, authenticated with GITHUB_TOKEN, GITLAB_TOKEN or GITEA_TOKEN, which can be
+// secret references, see readSecretRef
+func (f *providerFlags) provider(ctx context.Context) (provider, error) {
+	transport := f.transport()
+	switch f.name {
+	case "github":
+		baseURL := f.baseURL
+		if baseURL == "" {
+			baseURL = defaultGitHubURL()
+		}
+		token, err := envSecret(ctx, "GITHUB_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		return newGitHubClient(baseURL, token, transport), nil
+	case "gitlab":
+		baseURL := f.baseURL
+		if baseURL == "" {
+			baseURL = defaultGitLabAPIURL
+		}
+		token, err := envSecret(ctx, "GITLAB_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		return newGitLabClient(baseURL, token, transport), nil
+	case "gitea":
+		if f.baseURL == "" {
+			return nil, fmt.Errorf("error: --base-url is required for provider [%s]", f.name)
+		}
+		token, err := envSecret(ctx, "GITEA_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		return newGiteaClient(f.baseURL, token, transport), nil
+	}
+	return nil, fmt.Errorf("error: unknown provider [%s]", f.name)
+}
+
+func bearerHeader(scheme, token string) http.Header {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", scheme+" "+token)
+	}
+	return header
+}
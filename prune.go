@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runPrune(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		configFilePath      string
+		apply               bool
+		pr                  pullRequestFlags
+	)
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases")
+	fs.BoolVar(&apply, "apply", false, "write the pruned maintainers file instead of only printing the diff")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	pr.register(fs)
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	index, err := decodeIndexFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+
+	reasons := pruneReasons(config, maintainers, index, indexFilePath)
+	if len(reasons) == 0 {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+	var pruned []string
+	for _, m := range maintainers {
+		for _, chart := range m.Charts {
+			if reason, ok := reasons[chart.Name]; ok {
+				pruned = append(pruned, fmt.Sprintf("chart [%s] of [%s] %s", chart.Name, m.Name, reason))
+				fmt.Println(pruned[len(pruned)-1])
+			}
+		}
+	}
+	removeChartNodes(doc, func(team, chart string) bool {
+		_, ok := reasons[chart]
+		return ok
+	})
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s", unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if pr.create {
+		fmt.Println()
+		return pr.open(ctx, "prune", maintainersFilePath, updated, fmt.Sprintf("Prune %s from the maintainers file", pluralize(len(pruned), "chart")), pruned)
+	}
+	if !apply {
+		fmt.Println("\nRe-run with --apply to write these changes")
+		return nil
+	}
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\nwrote [%s]\n", maintainersFilePath)
+	return nil
+}
+
+// pruneReasons returns why each chart of the maintainers file that can be pruned should be, keyed by chart name:
+// charts absent from the index and charts whose latest version is deprecated
+func pruneReasons(config *Config, maintainers Maintainers, index *repo.IndexFile, indexFilePath string) map[string]string {
+	reasons := make(map[string]string)
+	for _, name := range maintainers.ChartNames() {
+		versions := validate.AliasedChartVersions(index, config.Aliases, name)
+		if len(versions) == 0 {
+			reasons[name] = fmt.Sprintf("does not exist in index file [%s]", indexFilePath)
+			continue
+		}
+		latest := versions[0]
+		for _, cv := range versions[1:] {
+			if newerVersion(cv.Version, latest.Version) {
+				latest = cv
+			}
+		}
+		if latest.Deprecated {
+			reasons[name] = fmt.Sprintf("is deprecated as of version [%s] in index file [%s]", latest.Version, indexFilePath)
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// pullRequestFlags let the commands editing the maintainers file open a GitHub pull request with their changes
+// instead of writing them, authenticated with GITHUB_TOKEN
+type pullRequestFlags struct {
+	create bool
+	repo   string
+	base   string
+	path   string
+	branch string
+}
+
+func (f *pullRequestFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.create, "create-pr", false, "commit the changes to a new branch and open a pull request instead of writing them")
+	fs.StringVar(&f.repo, "pr-repo", os.Getenv("GITHUB_REPOSITORY"), "owner/name of the GitHub repository the pull request is opened in")
+	fs.StringVar(&f.base, "pr-base", "", "branch the pull request is opened against, defaults to the default branch of the repository")
+	fs.StringVar(&f.path, "pr-path", "", "path of the maintainers file in the repository, defaults to --maintainers-file")
+	fs.StringVar(&f.branch, "pr-branch", "", "branch the changes are committed to, defaults to cowhand/<command>-<timestamp>")
+}
+
+// open commits contents as the maintainers file to a new branch and opens a pull request listing changes
+func (f *pullRequestFlags) open(ctx context.Context, command, maintainersFilePath string, contents []byte, title string, changes []string) error {
+	if f.repo == "" {
+		return errors.New("error: --pr-repo is required with --create-pr")
+	}
+	token, err := envSecret(ctx, "GITHUB_TOKEN")
+	if err != nil {
+		return err
+	}
+	client := newGitHubClient(defaultGitHubURL(), token, http.DefaultTransport)
+	base := f.base
+	if base == "" {
+		var err error
+		if base, err = client.defaultBranch(ctx, f.repo); err != nil {
+			return err
+		}
+	}
+	path := f.path
+	if path == "" {
+		path = diffName(maintainersFilePath)
+	}
+	branch := f.branch
+	if branch == "" {
+		name := strings.Join(strings.Fields(strings.ReplaceAll(command, "-", " ")), "-")
+		branch = fmt.Sprintf("cowhand/%s-%s", name, time.Now().UTC().Format("20060102150405"))
+	}
+	if err := client.createBranch(ctx, f.repo, branch, base); err != nil {
+		return err
+	}
+	if err := client.commitFile(ctx, f.repo, branch, path, title, contents); err != nil {
+		return err
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Changes made to `%s` by `cowhand %s`:\n\n", path, command)
+	for _, change := range changes {
+		fmt.Fprintf(&body, "- %s\n", change)
+	}
+	pr, err := client.createPullRequest(ctx, f.repo, branch, base, title, body.String())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("opened pull request #%d %s\n", pr.Number, pr.URL)
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// queryItem is what the --template of the query commands is executed with, Chart is nil for teams
+type queryItem struct {
+	Team  *Maintainer
+	Chart *Chart
+	// Kind and Match are the field search matched, e.g. slack and #team
+	Kind  string
+	Match string
+}
+
+// queryTemplateFlag is the --template of the query commands, executed once per result and followed by a newline so
+// scripts get exactly the fields they ask for, e.g. {{.Team.Contact.SlackChannel}}
+type queryTemplateFlag struct {
+	text string
+}
+
+func (f *queryTemplateFlag) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.text, "template", "", "go template printed for every result instead of the table, e.g. '{{.Team.Contact.SlackChannel}}', with .Team, .Chart and the join function")
+}
+
+func (f *queryTemplateFlag) set() bool {
+	return f.text != ""
+}
+
+func (f *queryTemplateFlag) write(w io.Writer, items []queryItem) error {
+	tmpl, err := template.New("template").Funcs(template.FuncMap{"join": strings.Join}).Option("missingkey=error").Parse(f.text)
+	if err != nil {
+		return fmt.Errorf("error: failed to parse --template: %w", err)
+	}
+	for _, item := range items {
+		if err := tmpl.Execute(w, item); err != nil {
+			return fmt.Errorf("error: failed to render --template: %w", err)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
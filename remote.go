@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+var remoteClient = &http.Client{Timeout: 5 * time.Minute, Transport: tracingTransport(http.DefaultTransport)}
+
+// indexCache is the on-disk cache of the indexes downloaded by fetch and of the remote maintainers files, set by the
+// flags of registerIndexCacheFlags
+var indexCache = struct {
+	dir     string
+	refresh bool
+}{dir: defaultIndexCacheDir()}
+
+// defaultIndexCacheDir returns the directory downloaded indexes and maintainers files are cached in, apart from the
+// API reads so that writes through the API do not invalidate them, or an empty string if the user cache directory
+// cannot be determined
+func defaultIndexCacheDir() string {
+	if dir := defaultCacheDir(); dir != "" {
+		return filepath.Join(dir, "index")
+	}
+	return ""
+}
+
+// registerIndexCacheFlags adds the flags of the index cache to the commands that read an index
+func registerIndexCacheFlags(fs *flag.FlagSet) {
+	fs.StringVar(&indexCache.dir, "index-cache-dir", defaultIndexCacheDir(), "directory downloaded indexes and maintainers files are cached in, empty disables caching")
+	fs.BoolVar(&indexCache.refresh, "refresh", false, "download remote indexes and maintainers files again instead of using the cached copy")
+}
+
+// indexClient downloads through the index cache. Cached files are served as long as the Cache-Control or Expires
+// headers of the server allow and revalidated with their ETag or Last-Modified afterwards, so the daemon polling an
+// unchanged file costs the server a 304 at most
+func indexClient() *http.Client {
+	transport := newCachingTransport(indexCache.dir, 0)
+	transport.refresh = indexCache.refresh
+	transport.cacheControl = true
+	return &http.Client{Timeout: remoteClient.Timeout, Transport: transport}
+}
+
+func isRemote(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
+
+// fetch downloads url, see openFetch
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	body, err := openFetch(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// openFetch starts downloading url with client, indexClient or remoteClient, and returns its body, transparently
+// decompressing gzip whether it was applied as a content encoding or the file itself is compressed, e.g.
+// index.yaml.gz, so that it can be decoded as it arrives
+func openFetch(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/yaml, application/x-yaml, text/yaml, application/json, application/gzip;q=0.9, */*;q=0.1")
+	// Setting Accept-Encoding disables the transport's own decompression, so gzip is handled below either way
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error: failed to fetch [%s]: %s", url, resp.Status)
+	}
+	// Proxies and login walls answer with HTML pages, fail with a clear error instead of a YAML parse error
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error: [%s] returned an HTML page instead of an index file", url)
+	}
+	return openMaybeGzip(resp.Body)
+}
+
+// openMaybeGzip returns the contents of r, decompressing them as they are read if they start with the gzip magic
+// number. Closing it closes r
+func openMaybeGzip(r io.ReadCloser) (io.ReadCloser, error) {
+	body := bufio.NewReader(r)
+	if magic, _ := body.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		return maybeGzipReader{gz, r}, nil
+	}
+	return maybeGzipReader{body, r}, nil
+}
+
+// maybeGzipReader reads the possibly decompressed contents of closer
+type maybeGzipReader struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r maybeGzipReader) Close() error {
+	return r.closer.Close()
+}
+
+// maintainersHeaderEnv names the environment variable holding the header remote maintainers files are downloaded
+// with, e.g. "Authorization: Bearer <token>" or "PRIVATE-TOKEN: <token>". A value without a colon is a bearer token
+const maintainersHeaderEnv = "COWHAND_MAINTAINERS_HEADER"
+
+// maintainersHeader returns the header a remote maintainers file is downloaded with: the one of
+// COWHAND_MAINTAINERS_HEADER, or GITHUB_TOKEN as a bearer token for the files hosted by GitHub. Either can be a
+// secret reference, see readSecretRef
+func maintainersHeader(ctx context.Context, url string) (name, value string, err error) {
+	header, err := envSecret(ctx, maintainersHeaderEnv)
+	if err != nil {
+		return "", "", err
+	}
+	if header != "" {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			return "Authorization", "Bearer " + strings.TrimSpace(header), nil
+		}
+		if name = strings.TrimSpace(name); name == "" || strings.ContainsAny(name, " \t") {
+			return "", "", fmt.Errorf("error: %s is not a header like [Authorization: Bearer <token>]", maintainersHeaderEnv)
+		}
+		return name, strings.TrimSpace(value), nil
+	}
+	if u, err := neturl.Parse(url); err != nil || (u.Host != "raw.githubusercontent.com" && u.Host != "github.com") {
+		return "", "", nil
+	}
+	token, err := envSecret(ctx, "GITHUB_TOKEN")
+	if err != nil || token == "" {
+		return "", "", err
+	}
+	return "Authorization", "Bearer " + token, nil
+}
+
+// fetchMaintainersFile downloads a maintainers file published over HTTP(S), e.g. the raw URL of the file in another
+// repository. It is cached like the indexes, see indexClient
+func fetchMaintainersFile(ctx context.Context, url string) (Maintainers, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/yaml, application/x-yaml, text/yaml, text/plain;q=0.9, */*;q=0.1")
+	name, value, err := maintainersHeader(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	client := indexClient()
+	if name != "" {
+		req.Header.Set(name, value)
+		// Go only drops Authorization and the cookies when the server redirects to another host, any other header like
+		// PRIVATE-TOKEN would follow the redirect, so it is deleted here and the token only goes where it was meant to
+		client.CheckRedirect = func(redirect *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if redirect.URL.Host != via[0].URL.Host {
+				redirect.Header.Del(name)
+			}
+			return nil
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error: failed to fetch maintainers file [%s]: %s", url, resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		return nil, fmt.Errorf("error: [%s] returned an HTML page instead of a maintainers file", url)
+	}
+	body, err := openMaybeGzip(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to decode maintainers file [%s]: %w", url, err)
+	}
+	ms, err := maintainers.Decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to decode maintainers file [%s]: %w", url, err)
+	}
+	return ms, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runRemoveChart(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		withCRD             bool
+	)
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("remove-chart", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.BoolVar(&withCRD, "with-crd", false, "also remove the <chart>-crd companion chart")
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" {
+		return errors.New("error: usage: cowhand remove-chart <chart> [--with-crd]")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	names := map[string]struct{}{chartName: {}}
+	if withCRD {
+		names[chartName+"-crd"] = struct{}{}
+	}
+	if m, _ := maintainers.FindChart(chartName); m == nil {
+		return fmt.Errorf("error: chart [%s] is not in maintainers file [%s]", chartName, maintainersFilePath)
+	}
+
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	var removed []string
+	removeChartNodes(doc, func(team, chart string) bool {
+		if _, ok := names[chart]; !ok {
+			return false
+		}
+		removed = append(removed, fmt.Sprintf("removed chart [%s] from [%s]", chart, team))
+		return true
+	})
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", strings.Join(removed, "\n"))
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// teamLabelPattern matches the label a team name ends with, e.g. team/area1 in "Neo Engineering Team (team/area1)"
+var teamLabelPattern = regexp.MustCompile(`\(([^()]+)\)\s*$`)
+
+func runRenameTeam(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		updateLabels        bool
+	)
+	var names []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		names, args = append(names, args[0]), args[1:]
+	}
+	fs := flag.NewFlagSet("rename-team", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.BoolVar(&updateLabels, "update-labels", false, "also replace the label derived from the team name, e.g. (team/area1), in the labels of its charts")
+	fs.Parse(args)
+	names = append(names, fs.Args()...)
+	if len(names) != 2 || strings.TrimSpace(names[1]) == "" {
+		return errors.New(`error: usage: cowhand rename-team "<old name>" "<new name>" [--update-labels]`)
+	}
+	newName := strings.TrimSpace(names[1])
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	m, err := maintainers.FindTeam(names[0])
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	if existing, _ := maintainers.FindTeamByName(newName); existing != nil && existing != m {
+		return fmt.Errorf("error: team [%s] already exists", newName)
+	}
+	var oldLabel, newLabel string
+	if updateLabels {
+		if oldLabel = teamLabel(m.Name); oldLabel == "" {
+			return fmt.Errorf("error: team [%s] has no label in its name to update, e.g. (team/area1)", m.Name)
+		}
+		if newLabel = teamLabel(newName); newLabel == "" {
+			return fmt.Errorf("error: team [%s] has no label in its name to update the charts with, e.g. (team/area1)", newName)
+		}
+	}
+
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	relabeled := 0
+	for _, team := range teamNodes(doc) {
+		name := mappingValue(team, "name")
+		if name == nil || name.Value != m.Name {
+			continue
+		}
+		name.Value = newName
+		if !updateLabels || oldLabel == newLabel {
+			continue
+		}
+		charts := mappingValue(team, "charts")
+		if charts == nil {
+			continue
+		}
+		for _, chart := range charts.Content {
+			if relabelChartNode(chart, oldLabel, newLabel) {
+				relabeled++
+			}
+		}
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\nrenamed team [%s] to [%s]\n", m.Name, newName)
+	if updateLabels {
+		fmt.Printf("replaced label [%s] with [%s] on %s\n", oldLabel, newLabel, pluralize(relabeled, "chart"))
+	}
+	return nil
+}
+
+// teamLabel returns the label a team name ends with in parentheses, or ""
+func teamLabel(team string) string {
+	match := teamLabelPattern.FindStringSubmatch(team)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1])
+}
+
+// relabelChartNode replaces oldLabel with newLabel in the githubLabels of a chart node, dropping it instead if the
+// chart already has newLabel, and reports whether the chart had oldLabel
+func relabelChartNode(chart *yaml.Node, oldLabel, newLabel string) bool {
+	labels := mappingValue(chart, "githubLabels")
+	if labels == nil || labels.Kind != yaml.SequenceNode {
+		return false
+	}
+	found, hasNew := false, false
+	for _, label := range labels.Content {
+		hasNew = hasNew || label.Value == newLabel
+	}
+	kept := labels.Content[:0]
+	for _, label := range labels.Content {
+		if label.Value == oldLabel {
+			found = true
+			if hasNew {
+				continue
+			}
+			label.Value = newLabel
+		}
+		kept = append(kept, label)
+	}
+	labels.Content = kept
+	return found
+}
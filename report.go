@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func runReport(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "release" {
+		return errors.New("error: usage: cowhand report release --from <index> --to <index>")
+	}
+	var (
+		maintainersFilePath string
+		fromIndexFilePath   string
+		toIndexFilePath     string
+	)
+	fs := flag.NewFlagSet("report release", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&fromIndexFilePath, "from", "", "path, http(s), oci:// or helm-cache:// URL of the index file of the previous release")
+	fs.StringVar(&toIndexFilePath, "to", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the index file of the new release")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args[1:])
+	if fromIndexFilePath == "" {
+		return errors.New("error: --from is required")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	from, err := decodeIndexFile(ctx, fromIndexFilePath)
+	if err != nil {
+		return err
+	}
+	to, err := decodeIndexFile(ctx, toIndexFilePath)
+	if err != nil {
+		return err
+	}
+	writeReleaseReport(os.Stdout, maintainers, releaseChanges(from, to))
+	return nil
+}
+
+type chartChange struct {
+	name     string
+	added    bool
+	versions []string
+}
+
+// releaseChanges returns every chart that is new in to or has versions in to that are not in from
+func releaseChanges(from, to *repo.IndexFile) []*chartChange {
+	var changes []*chartChange
+	for name, versions := range to.Entries {
+		previous := make(map[string]struct{})
+		for _, cv := range from.Entries[name] {
+			previous[cv.Version] = struct{}{}
+		}
+		change := &chartChange{name: name, added: len(from.Entries[name]) == 0}
+		for _, cv := range versions {
+			if _, ok := previous[cv.Version]; !ok {
+				change.versions = append(change.versions, cv.Version)
+			}
+		}
+		if len(change.versions) > 0 {
+			sortVersions(change.versions)
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].name < changes[j].name })
+	return changes
+}
+
+// writeReleaseReport prints the changes as markdown grouped by owning team, in maintainers file order
+func writeReleaseReport(w io.Writer, maintainers Maintainers, changes []*chartChange) {
+	byTeam := make(map[*Maintainer][]*chartChange)
+	var unowned []*chartChange
+	for _, change := range changes {
+		m, _ := maintainers.FindChartVersion(change.name, change.versions[0])
+		if m == nil {
+			unowned = append(unowned, change)
+			continue
+		}
+		byTeam[m] = append(byTeam[m], change)
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No charts were added or updated")
+		return
+	}
+	for _, m := range maintainers {
+		if len(byTeam[m]) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "## %s\n\n", m.Name)
+		if contacts := m.Contact.String(); contacts != "" {
+			fmt.Fprintf(w, "Contact: %s\n\n", contacts)
+		}
+		writeChartChanges(w, byTeam[m])
+	}
+	if len(unowned) > 0 {
+		fmt.Fprintf(w, "## No maintainers\n\n")
+		writeChartChanges(w, unowned)
+	}
+}
+
+func writeChartChanges(w io.Writer, changes []*chartChange) {
+	for _, change := range changes {
+		status := "updated"
+		if change.added {
+			status = "added"
+		}
+		fmt.Fprintf(w, "- `%s` %s: %s\n", change.name, status, strings.Join(change.versions, ", "))
+	}
+	fmt.Fprintln(w)
+}
+
+// sortVersions sorts versions newest first, ordering anything that is not valid semver last
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool { return newerVersion(versions[i], versions[j]) })
+}
+
+// newerVersion reports whether a is a greater semver than b, versions that do not parse are older than any that do
+func newerVersion(a, b string) bool {
+	va, erra := semver.NewVersion(a)
+	vb, errb := semver.NewVersion(b)
+	if erra != nil || errb != nil {
+		return erra == nil && errb != nil
+	}
+	return va.GreaterThan(vb)
+}
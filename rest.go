@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// restClient holds the JSON-over-HTTP plumbing shared by the code host providers
+type restClient struct {
+	name    string
+	baseURL string
+	header  http.Header
+	client  *http.Client
+}
+
+func newRESTClient(name, baseURL string, header http.Header, transport http.RoundTripper) *restClient {
+	return &restClient{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		header:  header,
+		client:  &http.Client{Transport: transport},
+	}
+}
+
+func (c *restClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send issues a request with a JSON body, decoding the response into out if it is not nil
+func (c *restClient) send(ctx context.Context, method, path string, in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+var nextLinkRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// getPaginated follows the Link headers returned on list endpoints, calling fn with each page body
+func (c *restClient) getPaginated(ctx context.Context, path string, fn func([]byte) error) error {
+	next := c.baseURL + path
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+		next = ""
+		if m := nextLinkRegexp.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+	}
+	return nil
+}
+
+func (c *restClient) do(req *http.Request) (*http.Response, error) {
+	for key, values := range c.header {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		var body struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return nil, fmt.Errorf("%s: %s %s: %s %s", c.name, req.Method, req.URL.Path, resp.Status, body.Message)
+	}
+	return resp, nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// Route sends the changes of the findings daemon notifies that match all of its criteria, an empty one matching
+// everything, to its own notifications and email addresses on top of the notifications of the config. E.g. a route
+// with the error severity and a Slack notification, and one with the warning severity and an email address
+type Route struct {
+	// Name identifies the route in the logs, its position in the config file if it is not set
+	Name string `yaml:"name"`
+	// Teams are the teams maintaining the charts of the findings
+	Teams []string `yaml:"teams"`
+	// Rules are the rules of the findings, those of validate or of the plugins
+	Rules      []string            `yaml:"rules"`
+	Severities []validate.Severity `yaml:"severities"`
+	// Charts are patterns of the charts of the findings, like those of the suggestions
+	Charts        []string       `yaml:"charts"`
+	Notifications []Notification `yaml:"notifications"`
+	Email         []string       `yaml:"email"`
+	// Digest batches the findings into a single digest, daily or weekly, of what changed since the previous one
+	// instead of notifying every change
+	Digest string `yaml:"digest"`
+}
+
+const (
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+)
+
+// validate returns an error, to follow the position of the route in the config file, if it has no destination or
+// an invalid criterion
+func (r Route) validate(config *Config) error {
+	if len(r.Notifications) == 0 && len(r.Email) == 0 {
+		return errors.New("without notifications or email")
+	}
+	for i, n := range r.Notifications {
+		if err := n.validate(); err != nil {
+			return fmt.Errorf("has notification [%d] %w", i, err)
+		}
+	}
+	if len(r.Email) > 0 {
+		if config.Email.Host == "" {
+			return errors.New("with email but the config file has no email host")
+		}
+		if _, err := emailAddresses(strings.Join(r.Email, ",")); err != nil {
+			return fmt.Errorf("with invalid email: %w", err)
+		}
+	}
+	if r.Digest != "" && r.Digest != digestDaily && r.Digest != digestWeekly {
+		return fmt.Errorf("with unknown digest [%s], use daily or weekly", r.Digest)
+	}
+	for _, severity := range r.Severities {
+		if severity != validate.SeverityError && severity != validate.SeverityWarning {
+			return fmt.Errorf("with unknown severity [%s], use error or warning", severity)
+		}
+	}
+	for _, pattern := range r.Charts {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("with invalid chart pattern [%s]", pattern)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the result matches every criterion of the route, the team of its chart is looked up in
+// the maintainers file
+func (r Route) matches(config *Config, maintainers Maintainers, result validate.Result) bool {
+	if len(r.Rules) > 0 && !slices.Contains(r.Rules, result.Rule) {
+		return false
+	}
+	if len(r.Severities) > 0 && !slices.Contains(r.Severities, result.Severity) {
+		return false
+	}
+	if len(r.Charts) > 0 && !slices.ContainsFunc(r.Charts, func(pattern string) bool {
+		ok, _ := path.Match(pattern, result.Chart)
+		return ok
+	}) {
+		return false
+	}
+	if len(r.Teams) > 0 {
+		m, _ := maintainers.FindChart(validate.CanonicalChartName(config.Aliases, result.Chart))
+		if m == nil || !slices.Contains(r.Teams, m.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns the part of the change the route matches
+func (r Route) filter(config *Config, maintainers Maintainers, change findingsChange) findingsChange {
+	return findingsChange{
+		Source:   change.Source,
+		Digest:   change.Digest,
+		New:      r.filterResults(config, maintainers, change.New),
+		Resolved: r.filterResults(config, maintainers, change.Resolved),
+	}
+}
+
+func (r Route) filterResults(config *Config, maintainers Maintainers, results validate.Results) validate.Results {
+	var matched validate.Results
+	for _, result := range results {
+		if r.matches(config, maintainers, result) {
+			matched = append(matched, result)
+		}
+	}
+	return matched
+}
+
+// key identifies the route in the logs and the digests of the state file
+func (r Route) key(i int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strconv.Itoa(i)
+}
+
+// window is how long the digests of the route are apart
+func (r Route) window() time.Duration {
+	if r.Digest == digestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// routeChange sends the part of the change every route of the config without a digest matches to its
+// destinations
+func routeChange(ctx context.Context, config *Config, maintainers Maintainers, change findingsChange) {
+	for i, r := range config.Routes {
+		if r.Digest != "" {
+			continue
+		}
+		if routed := r.filter(config, maintainers, change); len(routed.New) > 0 || len(routed.Resolved) > 0 {
+			r.send(ctx, config, r.key(i), routed)
+		}
+	}
+}
+
+// send sends the change to the destinations of the route, a destination that fails is logged and does not stop the
+// others
+func (r Route) send(ctx context.Context, config *Config, name string, change findingsChange) {
+	for _, n := range r.Notifications {
+		if err := n.send(ctx, &config.Templates, change); err != nil {
+			slog.Error("failed to notify", "route", name, "type", n.Type, "error", err)
+		}
+	}
+	if len(r.Email) > 0 {
+		to, _ := emailAddresses(strings.Join(r.Email, ","))
+		if err := sendEmail(ctx, config, to, change.templateData(), change.sections()); err != nil {
+			slog.Error("failed to email", "route", name, "email", strings.Join(r.Email, ", "), "error", err)
+		}
+	}
+	slog.Info("routed findings", "route", name, "digest", change.Digest, "new", len(change.New), "resolved", len(change.Resolved))
+}
+
+// digestState is what a digest route was last sent
+type digestState struct {
+	SentAt time.Time `json:"sentAt"`
+	// Findings are the findings the route matched when the digest was sent, the next digest is what changed since
+	Findings validate.Results `json:"findings"`
+}
+
+// sendDigests sends the digest of every route whose window elapsed since its previous digest, nothing is sent when
+// nothing changed in the window. It reports whether the digests changed, and must be saved
+func sendDigests(ctx context.Context, config *Config, maintainers Maintainers, source string, findings validate.Results, digests map[string]*digestState, now time.Time) bool {
+	changed := false
+	for i, r := range config.Routes {
+		if r.Digest == "" {
+			continue
+		}
+		key := r.key(i)
+		state := digests[key]
+		if state == nil {
+			state = &digestState{}
+			digests[key] = state
+		}
+		if now.Sub(state.SentAt) < r.window() {
+			continue
+		}
+		current := r.filterResults(config, maintainers, findings)
+		if current == nil {
+			current = validate.Results{}
+		}
+		change := findingsChange{Source: source, Digest: r.Digest, New: subtractResults(current, state.Findings), Resolved: subtractResults(state.Findings, current)}
+		if len(change.New) > 0 || len(change.Resolved) > 0 {
+			r.send(ctx, config, key, change)
+		}
+		state.SentAt, state.Findings = now, current
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func TestRouteMatches(t *testing.T) {
+	config := &Config{Aliases: map[string]string{"rancher-old": "rancher-webhook"}}
+	maintainers := Maintainers{
+		{Name: "team-a", Charts: []Chart{{Name: "rancher-webhook"}}},
+		{Name: "team-b", Charts: []Chart{{Name: "fleet"}}},
+	}
+	webhook := validate.Result{Rule: validate.RuleTeamAnnotation, Severity: validate.SeverityError, Chart: "rancher-webhook"}
+	renamed := validate.Result{Rule: validate.RuleUnownedChart, Severity: validate.SeverityWarning, Chart: "rancher-old"}
+	unowned := validate.Result{Rule: validate.RuleUnownedChart, Severity: validate.SeverityError, Chart: "rancher-new"}
+	tests := []struct {
+		name   string
+		route  Route
+		result validate.Result
+		want   bool
+	}{
+		{name: "no criteria", route: Route{}, result: unowned, want: true},
+		{name: "rule", route: Route{Rules: []string{validate.RuleUnownedChart}}, result: webhook, want: false},
+		{name: "severity", route: Route{Severities: []validate.Severity{validate.SeverityWarning}}, result: renamed, want: true},
+		{name: "other severity", route: Route{Severities: []validate.Severity{validate.SeverityWarning}}, result: webhook, want: false},
+		{name: "chart pattern", route: Route{Charts: []string{"rancher-*"}}, result: unowned, want: true},
+		{name: "other chart pattern", route: Route{Charts: []string{"fleet*"}}, result: webhook, want: false},
+		{name: "team", route: Route{Teams: []string{"team-a"}}, result: webhook, want: true},
+		{name: "team of a renamed chart", route: Route{Teams: []string{"team-a"}}, result: renamed, want: true},
+		{name: "other team", route: Route{Teams: []string{"team-b"}}, result: webhook, want: false},
+		{name: "team of an unowned chart", route: Route{Teams: []string{"team-a"}}, result: unowned, want: false},
+		{name: "every criterion", route: Route{Teams: []string{"team-a"}, Rules: []string{validate.RuleTeamAnnotation}, Charts: []string{"rancher-webhook"}}, result: webhook, want: true},
+	}
+	for _, tt := range tests {
+		if got := tt.route.matches(config, maintainers, tt.result); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRouteValidate(t *testing.T) {
+	notification := []Notification{{Type: "slack", URL: "https://hooks.slack.com/services/x"}}
+	tests := []struct {
+		name  string
+		route Route
+		valid bool
+	}{
+		{name: "notification", route: Route{Notifications: notification}, valid: true},
+		{name: "no destination", route: Route{Rules: []string{validate.RuleUnownedChart}}},
+		{name: "email without host", route: Route{Email: []string{"team@example.com"}}},
+		{name: "unknown digest", route: Route{Notifications: notification, Digest: "hourly"}},
+		{name: "unknown severity", route: Route{Notifications: notification, Severities: []validate.Severity{"critical"}}},
+		{name: "invalid chart pattern", route: Route{Notifications: notification, Charts: []string{"["}}},
+	}
+	for _, tt := range tests {
+		if err := tt.route.validate(&Config{}); (err == nil) != tt.valid {
+			t.Errorf("%s: got error %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	var posted []findingsChange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var change findingsChange
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			t.Error(err)
+		}
+		posted = append(posted, change)
+	}))
+	defer server.Close()
+	notification := []Notification{{Type: notificationWebhook, URL: server.URL}}
+	config := &Config{Routes: []Route{
+		{Name: "immediate", Notifications: notification},
+		{Name: "daily", Notifications: notification, Digest: digestDaily, Charts: []string{"fleet*"}},
+	}}
+	fleet := validate.Result{Rule: validate.RuleUnownedChart, Severity: validate.SeverityError, Chart: "fleet", Message: "fleet is unowned"}
+	crd := validate.Result{Rule: validate.RuleUnownedChart, Severity: validate.SeverityError, Chart: "fleet-crd", Message: "fleet-crd is unowned"}
+	other := validate.Result{Rule: validate.RuleUnownedChart, Severity: validate.SeverityError, Chart: "rancher-webhook", Message: "rancher-webhook is unowned"}
+
+	digests := make(map[string]*digestState)
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	steps := []struct {
+		after    time.Duration
+		findings validate.Results
+		changed  bool
+		// posted is whether the step posts a digest, of the new and resolved findings
+		posted   bool
+		new      validate.Results
+		resolved validate.Results
+	}{
+		{after: 0, findings: validate.Results{fleet, other}, changed: true, posted: true, new: validate.Results{fleet}},
+		// Nothing is sent before the window elapsed, whatever changed
+		{after: time.Hour, findings: validate.Results{crd}},
+		{after: 25 * time.Hour, findings: validate.Results{crd, other}, changed: true, posted: true, new: validate.Results{crd}, resolved: validate.Results{fleet}},
+		// Nothing is sent when nothing changed in the window, but the window starts again
+		{after: 50 * time.Hour, findings: validate.Results{crd}, changed: true},
+	}
+	for i, step := range steps {
+		posted = nil
+		if changed := sendDigests(context.Background(), config, nil, "maintainers.yaml", step.findings, digests, start.Add(step.after)); changed != step.changed {
+			t.Errorf("step %d: got changed %v, want %v", i, changed, step.changed)
+		}
+		switch {
+		case !step.posted && len(posted) > 0:
+			t.Errorf("step %d: posted %+v, want nothing", i, posted)
+		case step.posted && len(posted) != 1:
+			t.Errorf("step %d: posted %d digests, want 1", i, len(posted))
+		case step.posted:
+			got := posted[0]
+			if got.Source != "maintainers.yaml" || got.Digest != digestDaily || !reflect.DeepEqual(got.New.Strings(), step.new.Strings()) || !reflect.DeepEqual(got.Resolved.Strings(), step.resolved.Strings()) {
+				t.Errorf("step %d: posted %+v, want new %v and resolved %v", i, got, step.new, step.resolved)
+			}
+		}
+	}
+	if _, ok := digests["immediate"]; ok || digests["daily"] == nil || !digests["daily"].SentAt.Equal(start.Add(50*time.Hour)) {
+		t.Errorf("got digests %+v", digests)
+	}
+}
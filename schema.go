@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/pennyscissors/go-playground/pkg/maintainers"
+)
+
+func runSchema(ctx context.Context, args []string) error {
+	var output string
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	fs.StringVar(&output, "o", "-", "path the schema is written to, - for stdout")
+	fs.Parse(args)
+
+	// Generated rather than the embedded copy so the schema always matches the types of this binary
+	schema, err := maintainers.GenerateSchema()
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		_, err := os.Stdout.Write(schema)
+		return err
+	}
+	return os.WriteFile(output, schema, 0o644)
+}
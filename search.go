@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// searchResult is a field of the maintainers file matching the search term, the lower the score the better the match
+type searchResult struct {
+	kind  string
+	value string
+	team  *Maintainer
+	// chart is set when a chart name matched
+	chart *Chart
+	score int
+}
+
+func runSearch(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		tmpl                queryTemplateFlag
+	)
+	var term string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		term, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	tmpl.register(fs)
+	fs.Parse(args)
+	if term == "" {
+		term = fs.Arg(0)
+	}
+	if term == "" {
+		return errors.New("error: usage: cowhand search <term>")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	results := searchMaintainers(maintainers, term)
+	if len(results) == 0 {
+		return fmt.Errorf("error: nothing in maintainers file [%s] matches [%s]", maintainersFilePath, term)
+	}
+	if tmpl.set() {
+		var items []queryItem
+		for _, r := range results {
+			items = append(items, queryItem{Team: r.team, Chart: r.chart, Kind: r.kind, Match: r.value})
+		}
+		return tmpl.write(os.Stdout, items)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tMATCH\tTEAM")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.kind, r.value, r.team.Name)
+	}
+	return w.Flush()
+}
+
+// searchMaintainers matches term against the chart names, team names, emails and slack channels of the maintainers
+// file ignoring case, best matches first: exact, prefix, substring and then names a few typos away
+func searchMaintainers(maintainers Maintainers, term string) []searchResult {
+	var results []searchResult
+	seen := make(map[string]struct{})
+	match := func(kind, value string, team *Maintainer, chart *Chart) {
+		if value == "" {
+			return
+		}
+		score := searchScore(strings.ToLower(value), strings.ToLower(term))
+		key := kind + "\x00" + value + "\x00" + team.Name
+		if _, ok := seen[key]; ok || score < 0 {
+			return
+		}
+		seen[key] = struct{}{}
+		results = append(results, searchResult{kind, value, team, chart, score})
+	}
+	for _, m := range maintainers {
+		match("team", m.Name, m, nil)
+		match("email", m.Contact.Email, m, nil)
+		match("slack", m.Contact.SlackChannel, m, nil)
+		for i := range m.Charts {
+			match("chart", m.Charts[i].Name, m, &m.Charts[i])
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score < results[j].score })
+	return results
+}
+
+// searchScore ranks how well value matches term, or returns -1 if it does not
+func searchScore(value, term string) int {
+	switch {
+	case value == term:
+		return 0
+	case strings.HasPrefix(value, term):
+		return 1
+	case strings.Contains(value, term):
+		return 2
+	}
+	// Typos are only forgiven in terms long enough for them to still be telling
+	if len(term) < 4 {
+		return -1
+	}
+	if distance := editDistance(value, term); distance <= max(1, len(term)/4) {
+		return 2 + distance
+	}
+	return -1
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	vaultScheme      = "vault://"
+	awsSecretsScheme = "awssecrets://"
+	gcpSecretsScheme = "gcpsecrets://"
+)
+
+// resolveSecret returns the value of a secret of the config file, like a notification URL or a token: its
+// environment variables are expanded, e.g. ${SLACK_WEBHOOK_URL}, and the value is then read from the secret
+// manager it references, see readSecretRef
+func resolveSecret(ctx context.Context, value string) (string, error) {
+	return readSecretRef(ctx, os.ExpandEnv(value))
+}
+
+// envSecret is resolveSecret for the value of an environment variable, e.g. GITHUB_TOKEN, which is not expanded
+// again
+func envSecret(ctx context.Context, name string) (string, error) {
+	return readSecretRef(ctx, os.Getenv(name))
+}
+
+// readSecretRef reads the secret value references, or returns value itself if it is not a reference:
+//
+//   - secret://[<namespace>/]<name>?key=<key> is the key of a Kubernetes Secret
+//   - vault://<mount>/<path>?key=<field> is the field of a Vault KV secret, read with VAULT_ADDR and VAULT_TOKEN, or
+//     the token vault login saved
+//   - awssecrets://<name or ARN>[?key=<field>] is an AWS Secrets Manager secret, or the field of a JSON one, read
+//     with the standard AWS credential chain
+//   - gcpsecrets://<project>/<secret>[/<version>][?key=<field>] is a version of a GCP Secret Manager secret, the
+//     latest by default, read with the application default credentials
+//
+// A trailing newline, which secrets created from files usually end with, is trimmed
+func readSecretRef(ctx context.Context, value string) (string, error) {
+	var (
+		secret string
+		err    error
+	)
+	switch {
+	case strings.HasPrefix(value, secretScheme):
+		var o kubeObject
+		if o, err = parseKubeObject(value, ""); err == nil {
+			secret, err = o.readSecret(ctx)
+		}
+	case strings.HasPrefix(value, vaultScheme):
+		secret, err = readVaultSecret(ctx, value)
+	case strings.HasPrefix(value, awsSecretsScheme):
+		secret, err = readAWSSecret(ctx, value)
+	case strings.HasPrefix(value, gcpSecretsScheme):
+		secret, err = readGCPSecret(ctx, value)
+	default:
+		return value, nil
+	}
+	return strings.TrimRight(secret, "\r\n"), err
+}
+
+// splitSecretRef returns the part of the reference between its scheme and its query, and its key parameter
+func splitSecretRef(ref, scheme string) (name, key string, err error) {
+	name, query, _ := strings.Cut(strings.TrimPrefix(ref, scheme), "?")
+	values, err := neturl.ParseQuery(query)
+	if err != nil {
+		return "", "", fmt.Errorf("error: invalid secret reference [%s]: %w", ref, err)
+	}
+	if name = strings.Trim(name, "/"); name == "" {
+		return "", "", fmt.Errorf("error: secret reference [%s] does not name a secret", ref)
+	}
+	return name, values.Get("key"), nil
+}
+
+// secretField returns the field key of a secret holding a JSON object, or the whole secret if key is empty
+func secretField(ref, secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("error: secret [%s] is not a JSON object, remove ?key=%s", ref, key)
+	}
+	value, ok := fields[key].(string)
+	if !ok {
+		return "", fmt.Errorf("error: secret [%s] has no string field [%s]", ref, key)
+	}
+	return value, nil
+}
+
+// readVaultSecret reads the field of a KV secret, the KV version 2 API is tried first and version 1 if the mount
+// does not serve it
+func readVaultSecret(ctx context.Context, ref string) (string, error) {
+	name, key, err := splitSecretRef(ref, vaultScheme)
+	if err != nil {
+		return "", err
+	}
+	mount, path, _ := strings.Cut(name, "/")
+	if path == "" || key == "" {
+		return "", fmt.Errorf("error: secret reference [%s] does not name a field, use vault://<mount>/<path>?key=<field>", ref)
+	}
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("error: VAULT_ADDR is required to read [%s]", ref)
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			data, _ := os.ReadFile(filepath.Join(home, ".vault-token"))
+			token = strings.TrimSpace(string(data))
+		}
+	}
+	var v2 struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	found, err := getVault(ctx, addr+"/v1/"+mount+"/data/"+path, token, &v2)
+	fields := v2.Data.Data
+	if err == nil && !found {
+		var v1 struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		found, err = getVault(ctx, addr+"/v1/"+mount+"/"+path, token, &v1)
+		fields = v1.Data
+	}
+	if err != nil {
+		return "", fmt.Errorf("error: failed to read [%s]: %w", ref, err)
+	}
+	if !found {
+		return "", fmt.Errorf("error: vault secret [%s] does not exist", ref)
+	}
+	value, ok := fields[key].(string)
+	if !ok {
+		return "", fmt.Errorf("error: vault secret [%s] has no string field [%s]", ref, key)
+	}
+	return value, nil
+}
+
+// getVault decodes the answer of the Vault API into out, found is false if the path does not exist
+func getVault(ctx context.Context, url, token string, out interface{}) (found bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		var body struct {
+			Errors []string `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return false, fmt.Errorf("%s %s", resp.Status, strings.Join(body.Errors, ", "))
+	}
+	return true, json.NewDecoder(resp.Body).Decode(out)
+}
+
+func readAWSSecret(ctx context.Context, ref string) (string, error) {
+	name, key, err := splitSecretRef(ref, awsSecretsScheme)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		return "", fmt.Errorf("error: failed to read [%s]: %w", ref, err)
+	}
+	secret := aws.ToString(out.SecretString)
+	if out.SecretString == nil {
+		secret = string(out.SecretBinary)
+	}
+	return secretField(ref, secret, key)
+}
+
+// gcpSecretManagerURL is the endpoint of the GCP Secret Manager API
+var gcpSecretManagerURL = "https://secretmanager.googleapis.com/v1"
+
+func readGCPSecret(ctx context.Context, ref string) (string, error) {
+	name, key, err := splitSecretRef(ref, gcpSecretsScheme)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) == 2 {
+		parts = append(parts, "latest")
+	}
+	if len(parts) != 3 {
+		return "", fmt.Errorf("error: secret reference [%s] does not name a secret, use gcpsecrets://<project>/<secret>[/<version>]", ref)
+	}
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/projects/%s/secrets/%s/versions/%s:access", gcpSecretManagerURL, parts[0], parts[1], parts[2]), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error: failed to read [%s]: %w", ref, err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("error: failed to read [%s]: %w", ref, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error: failed to read [%s]: %s %s", ref, resp.Status, body.Error.Message)
+	}
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", errors.New("error: GCP Secret Manager answered an invalid payload")
+	}
+	return secretField(ref, string(data), key)
+}
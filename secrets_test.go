@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadVaultSecret(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv2/data/cowhand":
+			io.WriteString(w, `{"data":{"data":{"slack":"https://hooks.slack.com/v2\n","port":8080}}}`)
+		case "/v1/kv1/cowhand":
+			io.WriteString(w, `{"data":{"slack":"https://hooks.slack.com/v1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "token")
+	t.Setenv("SECRET_MOUNT", "kv2")
+
+	tests := []struct {
+		value string
+		want  string
+		err   string
+	}{
+		{value: "https://hooks.slack.com/plain", want: "https://hooks.slack.com/plain"},
+		{value: "vault://${SECRET_MOUNT}/cowhand?key=slack", want: "https://hooks.slack.com/v2"},
+		// A mount that does not serve the KV version 2 API is read with version 1
+		{value: "vault://kv1/cowhand?key=slack", want: "https://hooks.slack.com/v1"},
+		{value: "vault://kv2/cowhand?key=port", err: "has no string field [port]"},
+		{value: "vault://kv2/missing?key=slack", err: "does not exist"},
+		{value: "vault://kv2/cowhand", err: "does not name a field"},
+		{value: "vault://?key=slack", err: "does not name a secret"},
+		{value: "vault://kv2/cowhand?key=%zz", err: "invalid secret reference"},
+	}
+	for _, tt := range tests {
+		got, err := resolveSecret(context.Background(), tt.value)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: got error %v, want one containing %q", tt.value, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q and %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+
+	t.Setenv("VAULT_TOKEN", "other")
+	if _, err := resolveSecret(context.Background(), "vault://kv2/cowhand?key=slack"); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("got error %v, want the error of vault", err)
+	}
+}
+
+func TestSecretField(t *testing.T) {
+	tests := []struct {
+		secret string
+		key    string
+		want   string
+		err    string
+	}{
+		{secret: "plain", want: "plain"},
+		{secret: `{"token":"abc"}`, key: "token", want: "abc"},
+		{secret: `{"token":"abc"}`, key: "other", err: "has no string field [other]"},
+		{secret: "plain", key: "token", err: "is not a JSON object"},
+	}
+	for _, tt := range tests {
+		got, err := secretField("awssecrets://cowhand", tt.secret, tt.key)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s?key=%s: got error %v, want one containing %q", tt.secret, tt.key, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s?key=%s: got %q and %v, want %q", tt.secret, tt.key, got, err, tt.want)
+		}
+	}
+}
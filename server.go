@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+
+	"github.com/pennyscissors/go-playground/pkg/cowhandpb"
+)
+
+const statusContext = "cowhand/maintainers"
+
+// webhookQueueSize is how many checks wait for a worker, deliveries beyond it are refused so that GitHub records
+// them as failed and they can be redelivered
+const webhookQueueSize = 100
+
+func runServe(ctx context.Context, args []string) error {
+	var (
+		listen        string
+		grpcListen    string
+		webhookSecret string
+		checkWorkers  int
+		slackSecret   string
+		validateToken string
+		s             webhookServer
+		cf            cacheFlags
+		api           apiServer
+		configPath    string
+	)
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&listen, "listen", ":8080", "address the server listens on")
+	fs.StringVar(&grpcListen, "grpc-listen", "", "address the gRPC API listens on, e.g. :9090, disabled if empty")
+	fs.StringVar(&webhookSecret, "webhook-secret", os.Getenv("COWHAND_WEBHOOK_SECRET"), "secret GitHub signs webhook deliveries with, enables /webhook")
+	fs.StringVar(&validateToken, "validate-token", os.Getenv("COWHAND_VALIDATE_TOKEN"), "bearer token the requests to POST /v1/validate and the gRPC Validate carry, enables them")
+	fs.StringVar(&slackSecret, "slack-signing-secret", os.Getenv("COWHAND_SLACK_SIGNING_SECRET"), "signing secret of the Slack app whose slash commands are sent to /slack/commands, enables it, requires --maintainers-file")
+	fs.IntVar(&checkWorkers, "webhook-workers", defaultParallelism, "how many webhook deliveries are checked at once")
+	fs.StringVar(&s.maintainersPath, "maintainers-path", "maintainers.yaml", "path of the maintainers file inside the repository")
+	fs.StringVar(&s.indexPath, "index-path", "index.yaml", "path of the index file inside the repository")
+	fs.StringVar(&api.maintainersFilePath, "maintainers-file", "", "path to the maintainers file the /v1 ownership API and the dashboard serve, enables them")
+	fs.StringVar(&configPath, "config", defaultConfigFile, "path to the config file holding the chart aliases of the API, of /v1/validate and of /webhook, and the validateIndexURLs of /v1/validate")
+	fs.StringVar(&api.indexFilePath, "index-file", "", "path, http(s), oci:// or helm-cache:// URL of the index the API validates the maintainers file against on every reload, for the ownership metrics")
+	fs.DurationVar(&api.interval, "reload-interval", time.Minute, "how often the API reloads the maintainers file")
+	registerIndexCacheFlags(fs)
+	cf.register(fs)
+	fs.Parse(args)
+	if slackSecret != "" && api.maintainersFilePath == "" {
+		return errors.New("error: --slack-signing-secret requires --maintainers-file to answer from")
+	}
+	if checkWorkers < 1 {
+		return fmt.Errorf("error: invalid --webhook-workers [%d], it must be positive", checkWorkers)
+	}
+	if api.interval <= 0 {
+		return fmt.Errorf("error: invalid --reload-interval [%s], it must be positive", api.interval)
+	}
+	// The secrets can be references to a secret manager too, see readSecretRef
+	for _, secret := range []*string{&webhookSecret, &slackSecret, &validateToken} {
+		var err error
+		if *secret, err = readSecretRef(ctx, *secret); err != nil {
+			return err
+		}
+	}
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	metrics := newServerMetrics()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.handler())
+	grpcAPI := &grpcServer{}
+	if validateToken != "" {
+		grpcAPI.validator = &validateServer{config: config, token: []byte(validateToken)}
+		mux.Handle("POST /v1/validate", metrics.instrument("validate", grpcAPI.validator))
+	}
+	if webhookSecret != "" {
+		s.config = config
+		s.secret = []byte(webhookSecret)
+		token, err := envSecret(ctx, "GITHUB_TOKEN")
+		if err != nil {
+			return err
+		}
+		s.client = newGitHubClient(defaultGitHubURL(), token, cf.transport())
+		s.metrics = metrics
+		s.start(ctx, checkWorkers)
+		mux.Handle("/webhook", metrics.instrument("webhook", &s))
+	}
+	if api.maintainersFilePath != "" {
+		api.config = config
+		api.metrics = metrics
+		// The server does not start with a maintainers file it cannot load, later failures keep the last one
+		if err := api.load(ctx); err != nil {
+			return err
+		}
+		api.register(mux, metrics)
+		go api.reload(ctx)
+		grpcAPI.api = &api
+		if slackSecret != "" {
+			mux.Handle("POST /slack/commands", metrics.instrument("slack", &slackServer{secret: []byte(slackSecret), api: &api}))
+		}
+	}
+	if grpcListen != "" {
+		listener, err := net.Listen("tcp", grpcListen)
+		if err != nil {
+			return fmt.Errorf("error: failed to listen on [%s]: %w", grpcListen, err)
+		}
+		server := grpc.NewServer()
+		cowhandpb.RegisterCowhandServer(server, grpcAPI)
+		go func() {
+			<-ctx.Done()
+			server.GracefulStop()
+		}()
+		go func() {
+			if err := server.Serve(listener); err != nil {
+				slog.Error("gRPC server stopped", "error", err)
+			}
+		}()
+		slog.Info("listening for gRPC", "address", grpcListen)
+	}
+	// serve loads its files before listening, it is ready as soon as it answers
+	var h health
+	h.register(mux)
+	h.ready.Store(true)
+	// An interrupt stops accepting requests, waiting for the requests in flight
+	return listenAndServe(ctx, listen, mux)
+}
+
+// webhookServer re-validates the maintainers file whenever a push or pull request webhook is delivered, reporting
+// the result as a commit status and, for pull requests with problems, a comment
+type webhookServer struct {
+	// checks queues the checks for the workers, pending holds the checks queued or running so that the redeliveries
+	// of a commit are checked once
+	checks  chan webhookCheck
+	mu      sync.Mutex
+	pending map[webhookCheck]bool
+	// config holds the chart aliases, so a renamed chart is not reported as unowned
+	config          *Config
+	secret          []byte
+	client          *githubClient
+	maintainersPath string
+	indexPath       string
+	metrics         *serverMetrics
+}
+
+// webhookCheck is a commit to validate, number is the pull request to comment on, or 0
+type webhookCheck struct {
+	repo   string
+	sha    string
+	number int
+}
+
+type webhookEvent struct {
+	After       string `json:"after"`
+	Deleted     bool   `json:"deleted"`
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 25<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.validSignature(body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	repo := event.Repository.FullName
+	var c *webhookCheck
+	switch r.Header.Get("X-GitHub-Event") {
+	case "ping":
+		fmt.Fprintln(w, "pong")
+		return
+	case "push":
+		if event.Deleted {
+			break
+		}
+		c = &webhookCheck{repo: repo, sha: event.After}
+	case "pull_request":
+		if event.Action != "opened" && event.Action != "synchronize" && event.Action != "reopened" {
+			break
+		}
+		c = &webhookCheck{repo: repo, sha: event.PullRequest.Head.SHA, number: event.Number}
+	}
+	if c != nil && !s.enqueue(*c) {
+		http.Error(w, "too many checks queued", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// start runs the workers checking the queued deliveries until ctx is done
+func (s *webhookServer) start(ctx context.Context, workers int) {
+	s.checks = make(chan webhookCheck, webhookQueueSize)
+	s.pending = make(map[webhookCheck]bool)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case c := <-s.checks:
+					s.check(ctx, c.repo, c.sha, c.number)
+					s.mu.Lock()
+					delete(s.pending, c)
+					s.mu.Unlock()
+				}
+			}
+		}()
+	}
+}
+
+// enqueue queues c unless the same check is already queued or running, it returns false if the queue is full
+func (s *webhookServer) enqueue(c webhookCheck) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[c] {
+		return true
+	}
+	select {
+	case s.checks <- c:
+		s.pending[c] = true
+		return true
+	default:
+		return false
+	}
+}
+
+// validSignature checks the HMAC-SHA256 GitHub computes over the payload with the webhook secret
+func (s *webhookServer) validSignature(body []byte, signature string) bool {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// check validates the maintainers file at sha and reports the result, number is the pull request to comment on, or 0
+func (s *webhookServer) check(ctx context.Context, repo, sha string, number int) {
+	problems, err := s.validate(ctx, repo, sha)
+	if err != nil {
+		slog.Error("failed to validate", "repo", repo, "sha", sha, "error", err)
+		s.client.createCommitStatus(ctx, repo, sha, "error", statusContext, truncate(err.Error(), 140))
+		return
+	}
+	slog.Info("validated", "repo", repo, "sha", sha, "problems", len(problems))
+	state, description := "success", "maintainers file is valid"
+	if len(problems) > 0 {
+		state, description = "failure", pluralize(len(problems), "problem")+" found in the maintainers file"
+	}
+	if err := s.client.createCommitStatus(ctx, repo, sha, state, statusContext, description); err != nil {
+		slog.Error("failed to set status", "repo", repo, "sha", sha, "error", err)
+	}
+	if number == 0 || len(problems) == 0 {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "cowhand found problems with `%s` at %s:\n\n```\n", s.maintainersPath, sha)
+	for _, problem := range problems {
+		fmt.Fprintln(&b, problem)
+	}
+	b.WriteString("```\n")
+	if err := s.client.createComment(ctx, repo, number, b.String()); err != nil {
+		slog.Error("failed to comment", "repo", repo, "pullRequest", number, "error", err)
+	}
+}
+
+func (s *webhookServer) validate(ctx context.Context, repo, sha string) ([]string, error) {
+	data, err := s.client.getFileContents(ctx, repo, s.maintainersPath, sha)
+	if err != nil {
+		return nil, err
+	}
+	var maintainers Maintainers
+	if err := decodeYAMLFile(bytes.NewReader(data), &maintainers); err != nil {
+		return nil, err
+	}
+	data, err = s.client.getFileContents(ctx, repo, s.indexPath, sha)
+	if err != nil {
+		return nil, err
+	}
+	index, err := decodeIndex(data, s.indexPath)
+	if err != nil {
+		return nil, err
+	}
+	results, err := validateMaintainersResults(ctx, s.config, maintainers, index, s.maintainersPath, s.indexPath)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.recordValidation(repo, results)
+	return results.Strings(), nil
+}
+
+// truncate cuts s to at most n bytes without splitting a rune, GitHub refuses descriptions that are not UTF-8
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
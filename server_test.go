@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// webhookSignature is the X-Hub-Signature-256 header GitHub sends with body
+func webhookSignature(secret []byte, body string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookServerSignature(t *testing.T) {
+	s := &webhookServer{secret: []byte("secret")}
+	body := `{"zen": "Keep it logically awesome."}`
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		want      int
+	}{
+		{name: "valid", body: body, signature: webhookSignature(s.secret, body), want: http.StatusOK},
+		{name: "missing", body: body, want: http.StatusUnauthorized},
+		{name: "other secret", body: body, signature: webhookSignature([]byte("other"), body), want: http.StatusUnauthorized},
+		{name: "other body", body: body + " ", signature: webhookSignature(s.secret, body), want: http.StatusUnauthorized},
+		{name: "not hex", body: body, signature: "sha256=zz", want: http.StatusUnauthorized},
+		{name: "sha1", body: body, signature: "sha1=" + strings.TrimPrefix(webhookSignature(s.secret, body), "sha256="), want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+			req.Header.Set("X-GitHub-Event", "ping")
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookServerQueue(t *testing.T) {
+	// Without workers the checks stay queued, the queue holds two of them
+	s := &webhookServer{secret: []byte("secret"), checks: make(chan webhookCheck, 2), pending: make(map[webhookCheck]bool)}
+	deliver := func(sha string) int {
+		body := `{"after": "` + sha + `", "repository": {"full_name": "rancher/charts"}}`
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "push")
+		req.Header.Set("X-Hub-Signature-256", webhookSignature(s.secret, body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w.Code
+	}
+	for _, delivery := range []struct {
+		sha  string
+		want int
+	}{
+		{"a", http.StatusAccepted},
+		// A redelivery of a queued commit is not checked twice
+		{"a", http.StatusAccepted},
+		{"b", http.StatusAccepted},
+		{"c", http.StatusServiceUnavailable},
+	} {
+		if got := deliver(delivery.sha); got != delivery.want {
+			t.Errorf("delivery of [%s]: got status %d, want %d", delivery.sha, got, delivery.want)
+		}
+	}
+	if len(s.checks) != 2 {
+		t.Errorf("got %d queued checks, want 2", len(s.checks))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"a longer description", 10, "a longe..."},
+		// é is two bytes, cutting after its first byte would leave invalid UTF-8
+		{"chart café is unowned", 13, "chart caf..."},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.n)
+		if got != tt.want || len(got) > tt.n || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
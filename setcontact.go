@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func runSetContact(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		email               string
+		slackChannel        string
+		contactURL          string
+	)
+	var team string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		team, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("set-contact", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&email, "email", "", "email address of the team, an empty value clears it")
+	fs.StringVar(&slackChannel, "slack", "", "slack channel of the team, an empty value clears it")
+	fs.StringVar(&contactURL, "url", "", "url of the team, e.g. its wiki page, an empty value clears it")
+	fs.Parse(args)
+	if team == "" {
+		team = fs.Arg(0)
+	}
+	// Only the fields passed on the command line are changed
+	fields := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "email":
+			fields["email"] = strings.TrimSpace(email)
+		case "slack":
+			fields["slackChannel"] = strings.TrimSpace(slackChannel)
+		case "url":
+			fields["url"] = strings.TrimSpace(contactURL)
+		}
+	})
+	if team == "" || len(fields) == 0 {
+		return errors.New("error: usage: cowhand set-contact <team> [--email <address>] [--slack <channel>] [--url <url>]")
+	}
+	if err := validateContactFields(fields); err != nil {
+		return err
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	m, err := maintainers.FindTeam(team)
+	if err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	for _, t := range teamNodes(doc) {
+		if nodeName(t) == m.Name {
+			setContactNode(t, fields)
+		}
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	diff := unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated)
+	if diff == "" {
+		fmt.Printf("contact of [%s] is already up to date\n", m.Name)
+		return nil
+	}
+	fmt.Print(diff)
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\nupdated the contact of [%s]\n", m.Name)
+	return nil
+}
+
+// validateContactFields checks the email and url of contact fields keyed by their yaml name, empty values clear the
+// field and are valid
+func validateContactFields(fields map[string]string) error {
+	if value := fields["email"]; value != "" {
+		if _, err := mail.ParseAddress(value); err != nil {
+			return fmt.Errorf("error: email [%s] is not a valid address", value)
+		}
+	}
+	if value := fields["url"]; value != "" {
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("error: url [%s] is not an absolute url", value)
+		}
+	}
+	return nil
+}
+
+// setContactNode sets the contact fields of a team node, keyed by their yaml name, adding the contact right after the
+// team name if it has none
+func setContactNode(team *yaml.Node, fields map[string]string) {
+	contact := mappingValue(team, "contact")
+	if contact == nil || contact.Kind != yaml.MappingNode {
+		contact = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(team, "contact", contact)
+		// Contact goes right after the name, like in the rest of the file
+		if n := len(team.Content); n > 4 {
+			team.Content = append(team.Content[:2], append(team.Content[n-2:], team.Content[2:n-2]...)...)
+		}
+	}
+	// Keys in the order of Contact, existing values keep their quoting
+	for _, key := range []string{"email", "slackChannel", "url"} {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if node := mappingValue(contact, key); node != nil {
+			node.Value = value
+			continue
+		}
+		setMappingValue(contact, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+	}
+}
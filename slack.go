@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// slackServer answers the Slack slash commands /whomaintains <chart> and /cowhand status from the maintainers file of
+// the API, Slack signs its requests with the signing secret of the app
+type slackServer struct {
+	secret []byte
+	api    *apiServer
+}
+
+// slackResponse is only shown to the user who ran the command
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func (s *slackServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.validSignature(body, r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), time.Now()) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	text := s.command(form.Get("command"), strings.Fields(form.Get("text")))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(slackResponse{ResponseType: "ephemeral", Text: text})
+}
+
+// validSignature checks the v0 HMAC-SHA256 Slack computes over the timestamp and the body, refusing requests older
+// than five minutes so that they cannot be replayed
+func (s *slackServer) validSignature(body []byte, timestamp, signature string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || now.Sub(time.Unix(seconds, 0)).Abs() > 5*time.Minute {
+		return false
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "v0="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+func (s *slackServer) command(command string, args []string) string {
+	switch {
+	case command == "/whomaintains" && len(args) == 1:
+		return s.whoMaintains(args[0])
+	case command == "/cowhand" && len(args) == 1 && args[0] == "status":
+		return s.status()
+	case command == "/cowhand" && len(args) == 2 && args[0] == "who":
+		return s.whoMaintains(args[1])
+	}
+	return "usage: `/whomaintains <chart>`, `/cowhand who <chart>` or `/cowhand status`"
+}
+
+// whoMaintains describes the team maintaining the chart and the findings about it, like cowhand who
+func (s *slackServer) whoMaintains(chartName string) string {
+	maintainers, _ := s.api.snapshot()
+	m, chart, err := s.api.findChart(maintainers, chartName, "")
+	if err != nil {
+		if suggestions := similarChartNames(maintainers.ChartNames(), chartName); len(suggestions) > 0 {
+			return fmt.Sprintf("%s, did you mean %s?", err, "`"+strings.Join(suggestions, "`, `")+"`")
+		}
+		return err.Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*", chart.Name)
+	if chart.MaintainedVersions != "" {
+		fmt.Fprintf(&b, " (%s)", chart.MaintainedVersions)
+	}
+	fmt.Fprintf(&b, " is maintained by *%s*\n", m.Name)
+	for _, contact := range []struct{ name, value string }{
+		{"email", m.Contact.Email},
+		{"slack", m.Contact.SlackChannel},
+		{"url", m.Contact.URL},
+	} {
+		if contact.value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", contact.name, contact.value)
+		}
+	}
+	if len(chart.GithubLabels) > 0 {
+		fmt.Fprintf(&b, "labels: %s\n", strings.Join(chart.GithubLabels, ", "))
+	}
+	validation := s.api.lastValidation()
+	if validation != nil {
+		var findings []string
+		for _, f := range validation.findings {
+			if f.Chart == chart.Name {
+				findings = append(findings, "• "+f.String())
+			}
+		}
+		if len(findings) == 0 {
+			b.WriteString("no open findings\n")
+		} else {
+			fmt.Fprintf(&b, "%s:\n%s\n", pluralize(len(findings), "open finding"), strings.Join(findings, "\n"))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// status summarizes the maintainers file and its last validation
+func (s *slackServer) status() string {
+	maintainers, loadedAt := s.api.snapshot()
+	charts := 0
+	for _, m := range maintainers {
+		charts += len(m.Charts)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*: %s and %s, loaded %s", s.api.maintainersFilePath, pluralize(len(maintainers), "team"), pluralize(charts, "chart"), loadedAt.UTC().Format(time.RFC3339))
+	validation := s.api.lastValidation()
+	if validation == nil {
+		return b.String()
+	}
+	errs, warnings := 0, 0
+	for _, f := range validation.findings {
+		if f.Severity == validate.SeverityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	fmt.Fprintf(&b, "\nagainst *%s*: %s, %s, %d of %s unowned, validated %s", s.api.indexFilePath,
+		pluralize(errs, "error"), pluralize(warnings, "warning"), len(validation.unowned), pluralize(validation.indexCharts, "index chart"),
+		validation.validatedAt.UTC().Format(time.RFC3339))
+	return b.String()
+}
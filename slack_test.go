@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slackSignature is the X-Slack-Signature header Slack sends with body at timestamp
+func slackSignature(secret []byte, timestamp, body string) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlackServerValidSignature(t *testing.T) {
+	s := &slackServer{secret: []byte("secret")}
+	now := time.Unix(1700000000, 0)
+	body := "command=%2Fwhomaintains&text=fleet"
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	type test struct {
+		name      string
+		body      string
+		timestamp string
+		signature string
+		want      bool
+	}
+	tests := []test{
+		{name: "valid", body: body, timestamp: timestamp, signature: slackSignature(s.secret, timestamp, body), want: true},
+		{name: "missing", body: body, timestamp: timestamp},
+		{name: "other secret", body: body, timestamp: timestamp, signature: slackSignature([]byte("other"), timestamp, body)},
+		{name: "other body", body: body + "x", timestamp: timestamp, signature: slackSignature(s.secret, timestamp, body)},
+		{name: "not hex", body: body, timestamp: timestamp, signature: "v0=zz"},
+		{name: "no timestamp", body: body, signature: slackSignature(s.secret, "", body)},
+	}
+	// A signed request is refused more than five minutes away from its timestamp, so that it cannot be replayed
+	for _, offset := range []time.Duration{-6 * time.Minute, 6 * time.Minute} {
+		ts := strconv.FormatInt(now.Add(offset).Unix(), 10)
+		tests = append(tests, test{name: "timestamp " + offset.String(), body: body, timestamp: ts, signature: slackSignature(s.secret, ts, body)})
+	}
+	for _, tt := range tests {
+		if got := s.validSignature([]byte(tt.body), tt.timestamp, tt.signature, now); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlackServerServeHTTP(t *testing.T) {
+	s := &slackServer{secret: []byte("secret")}
+	body := "command=%2Fcowhand&text=help"
+	send := func(signature string) *httptest.ResponseRecorder {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		if signature == "" {
+			signature = slackSignature(s.secret, timestamp, body)
+		}
+		req.Header.Set("X-Slack-Signature", signature)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+	if w := send("v0=00"); w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned request: got status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	w := send("")
+	var resp slackResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || resp.ResponseType != "ephemeral" || !strings.HasPrefix(resp.Text, "usage: ") {
+		t.Errorf("signed request: got status %d and %+v", w.Code, resp)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func runSplit(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		out                 string
+		force               bool
+	)
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&out, "out", "./maintainers.d", "directory a file per team is written to, every command reads it with --maintainers-file")
+	fs.BoolVar(&force, "force", false, "overwrite the team files already in the directory")
+	fs.Parse(args)
+
+	doc, _, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	files := make(map[string]*yaml.Node)
+	var names []string
+	for _, team := range teamNodes(doc) {
+		name := backstageName(nodeName(team))
+		if name == "" {
+			name = "team"
+		}
+		// Teams whose names only differ in punctuation get a numbered file
+		for i, base := 2, name; files[name+".yaml"] != nil; i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		files[name+".yaml"] = team
+		names = append(names, name+".yaml")
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	if !force {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(out, name)); err == nil {
+				return fmt.Errorf("error: [%s] already exists, use --force to overwrite it", filepath.Join(out, name))
+			}
+		}
+	}
+	for _, name := range names {
+		// Each file is a maintainers file of its own, so the edit commands work on it too
+		teamDoc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{files[name]}}}}
+		data, err := encodeMaintainersNode(teamDoc, nil)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(out, name), data, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("wrote team [%s] to [%s]\n", nodeName(files[name]), filepath.Join(out, name))
+	}
+	return nil
+}
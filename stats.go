@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// maintainersStats is the output of stats, Unowned is nil when no index was read
+type maintainersStats struct {
+	Teams          int            `json:"teams"`
+	Charts         int            `json:"charts"`
+	GenerateIssue  int            `json:"generateIssue"`
+	ChartsPerTeam  map[string]int `json:"chartsPerTeam"`
+	Labels         map[string]int `json:"labels"`
+	UnlabeledCount int            `json:"unlabeled"`
+	Unowned        []string       `json:"unowned,omitempty"`
+}
+
+func runStats(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		configFilePath      string
+		output              string
+	)
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file the unowned charts are counted from")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases")
+	fs.StringVar(&output, "output", "text", "output format, text or json")
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+	if output != "text" && output != "json" {
+		return fmt.Errorf("error: unknown output [%s], use text or json", output)
+	}
+	explicitIndex := false
+	fs.Visit(func(f *flag.Flag) { explicitIndex = explicitIndex || f.Name == "index-file" })
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	stats := maintainersStats{Teams: len(maintainers), ChartsPerTeam: make(map[string]int), Labels: make(map[string]int)}
+	for _, m := range maintainers {
+		stats.ChartsPerTeam[m.Name] = len(m.Charts)
+		for _, chart := range m.Charts {
+			stats.Charts++
+			if chart.GenerateIssue {
+				stats.GenerateIssue++
+			}
+			if len(chart.GithubLabels) == 0 {
+				stats.UnlabeledCount++
+			}
+			for _, label := range chart.GithubLabels {
+				stats.Labels[label]++
+			}
+		}
+	}
+	// Without an index the unowned charts are left out, unless the index was asked for
+	index, err := decodeIndexNamesFile(ctx, indexFilePath)
+	if err != nil && explicitIndex {
+		return err
+	}
+	if err == nil {
+		stats.Unowned = validate.OrphanCharts(config.Aliases, maintainers, index)
+		if stats.Unowned == nil {
+			stats.Unowned = []string{}
+		}
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "teams\t%d\n", stats.Teams)
+	fmt.Fprintf(w, "charts\t%d\n", stats.Charts)
+	fmt.Fprintf(w, "generateIssue\t%d\n", stats.GenerateIssue)
+	fmt.Fprintf(w, "unlabeled\t%d\n", stats.UnlabeledCount)
+	if stats.Unowned != nil {
+		fmt.Fprintf(w, "unowned\t%d\n", len(stats.Unowned))
+	}
+	fmt.Fprintln(w, "\nTEAM\tCHARTS")
+	for _, m := range maintainers {
+		fmt.Fprintf(w, "%s\t%d\n", m.Name, len(m.Charts))
+	}
+	fmt.Fprintln(w, "\nLABEL\tCHARTS")
+	labels := make([]string, 0, len(stats.Labels))
+	for label := range stats.Labels {
+		labels = append(labels, label)
+	}
+	// Most used labels first
+	sort.Slice(labels, func(i, j int) bool {
+		if stats.Labels[labels[i]] != stats.Labels[labels[j]] {
+			return stats.Labels[labels[i]] > stats.Labels[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	for _, label := range labels {
+		fmt.Fprintf(w, "%s\t%d\n", label, stats.Labels[label])
+	}
+	if len(stats.Unowned) > 0 {
+		fmt.Fprintln(w, "\nUNOWNED")
+		for _, name := range stats.Unowned {
+			fmt.Fprintln(w, name)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runSync(ctx context.Context, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runSyncCharts(ctx, args)
+	}
+	if args[0] != "chart-metadata" {
+		return errors.New("error: usage: cowhand sync [chart-metadata] [--apply]")
+	}
+	var (
+		maintainersFilePath string
+		packagesDir         string
+		configFilePath      string
+		stampAnnotation     bool
+		apply               bool
+	)
+	fs := flag.NewFlagSet("sync chart-metadata", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&packagesDir, "packages-dir", "./packages", "directory holding the Chart.yaml of every package")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the team suggestions for charts missing from the maintainers file")
+	fs.BoolVar(&stampAnnotation, "stamp-annotation", false, "also set the "+validate.MaintainerTeamAnnotation+" annotation to the owning team")
+	fs.BoolVar(&apply, "apply", false, "write the changes instead of only printing them")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	fs.Parse(args[1:])
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	p, err := planChartMetadata(config, maintainers, packagesDir, stampAnnotation)
+	if err != nil {
+		return err
+	}
+	p.print(os.Stdout)
+	if !apply || p.empty() {
+		if !p.empty() {
+			fmt.Println("\nRe-run with --apply to write these changes")
+		}
+		return nil
+	}
+	fmt.Println()
+	return p.apply(os.Stdout)
+}
+
+// runSyncCharts adds the charts of the index missing from the maintainers file to the unassigned team of the
+// config, or to their suggested team with --suggest
+func runSyncCharts(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		indexFilePath       string
+		configFilePath      string
+		suggest             bool
+		apply               bool
+		pr                  pullRequestFlags
+	)
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&indexFilePath, "index-file", defaultIndexFile, "path, http(s), oci:// or helm-cache:// URL of the chart repository index file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the unassigned team, aliases and team suggestions")
+	fs.BoolVar(&suggest, "suggest", false, "add each chart to its suggested team when there is one instead of the unassigned team")
+	fs.BoolVar(&apply, "apply", false, "write the changes instead of only printing the diff")
+	fs.BoolVar(&apply, "yes", false, "alias for --apply")
+	pr.register(fs)
+	registerIndexCacheFlags(fs)
+	fs.Parse(args)
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	index, err := decodeIndexFile(ctx, indexFilePath)
+	if err != nil {
+		return err
+	}
+	doc, original, err := decodeMaintainersNode(maintainersFilePath)
+	if err != nil {
+		return err
+	}
+
+	var added []string
+	for _, name := range validate.OrphanCharts(config.Aliases, maintainers, index) {
+		team, suggestedTeam := config.unassignedTeam(), ""
+		if suggested := validate.SuggestTeam(config.Suggestions, maintainers, name); suggested != "" {
+			// The suggestions of the config can name a team that is not in the maintainers file
+			if m, err := maintainers.FindTeam(suggested); err != nil {
+				fmt.Printf("warning: suggested team [%s] of chart [%s] is not a team of the maintainers file\n", suggested, name)
+			} else if suggestedTeam = m.Name; suggest {
+				team = m.Name
+			}
+		}
+		var value yaml.Node
+		if err := value.Encode(Chart{Name: name, GithubLabels: []string{}}); err != nil {
+			return err
+		}
+		if err := insertChartNode(doc, team, name, &value); err != nil {
+			// The unassigned team is created on first use
+			doc.Content[0].Content = append(doc.Content[0].Content, newTeamNode(team))
+			if err := insertChartNode(doc, team, name, &value); err != nil {
+				return err
+			}
+		}
+		line := fmt.Sprintf("added chart [%s] to [%s]", name, team)
+		if suggestedTeam != "" && suggestedTeam != team {
+			line += fmt.Sprintf(", suggested team [%s]", suggestedTeam)
+		}
+		added = append(added, line)
+	}
+	if len(added) == 0 {
+		fmt.Println("The maintainers file has every chart of the index")
+		return nil
+	}
+	updated, err := encodeMaintainersNode(doc, original)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unifiedDiff("a/"+diffName(maintainersFilePath), "b/"+diffName(maintainersFilePath), original, updated))
+	if pr.create {
+		fmt.Println()
+		return pr.open(ctx, "sync", maintainersFilePath, updated, fmt.Sprintf("Add %s of the index to the maintainers file", pluralize(len(added), "chart")), added)
+	}
+	if !apply {
+		fmt.Println()
+		for _, a := range added {
+			fmt.Println(a)
+		}
+		fmt.Println("\nRe-run with --apply to write these changes")
+		return nil
+	}
+	if err := os.WriteFile(maintainersFilePath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Println()
+	for _, a := range added {
+		fmt.Println(a)
+	}
+	return nil
+}
+
+// newTeamNode returns the node of a team without contacts or charts
+func newTeamNode(name string) *yaml.Node {
+	var node yaml.Node
+	node.Encode(&Maintainer{Name: name})
+	return &node
+}
+
+// chartMaintainer is an entry of the maintainers block of a Chart.yaml
+type chartMaintainer struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email,omitempty"`
+	URL   string `yaml:"url,omitempty"`
+}
+
+// planChartMetadata plans setting the owning team as a maintainer in every Chart.yaml under packagesDir, and as its
+// validate.MaintainerTeamAnnotation if stampAnnotation is set. Entries named after another team of the maintainers
+// file are replaced, any other maintainers are kept
+func planChartMetadata(config *Config, maintainers Maintainers, packagesDir string, stampAnnotation bool) (*plan, error) {
+	teams := make(map[string]struct{})
+	for _, m := range maintainers {
+		teams[m.Name] = struct{}{}
+	}
+	p := &plan{}
+	// Chart.yaml files are rewritten in place, so they are read from where they are on disk
+	err := walkCharts(os.DirFS(packagesDir), ".", func(name string) error {
+		path := filepath.Join(packagesDir, filepath.FromSlash(name))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("error: failed to decode [%s]: %w", path, err)
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			return fmt.Errorf("error: [%s] is not a mapping", path)
+		}
+		root := doc.Content[0]
+		var metadata struct {
+			Name        string            `yaml:"name"`
+			Version     string            `yaml:"version"`
+			Maintainers []chartMaintainer `yaml:"maintainers"`
+			Annotations map[string]string `yaml:"annotations"`
+		}
+		if err := root.Decode(&metadata); err != nil {
+			return fmt.Errorf("error: failed to decode [%s]: %w", path, err)
+		}
+		m, _ := maintainers.FindChartVersion(metadata.Name, metadata.Version)
+		if m == nil {
+			warning := fmt.Sprintf("warning: chart [%s] in [%s] is not in the maintainers file", metadata.Name, path)
+			if team := validate.SuggestTeam(config.Suggestions, maintainers, metadata.Name); team != "" {
+				warning += fmt.Sprintf(", suggested team [%s]", team)
+			}
+			fmt.Println(warning)
+			return nil
+		}
+		team := chartMaintainer{Name: m.Name, Email: m.Contact.Email, URL: m.Contact.URL}
+		updated := []chartMaintainer{team}
+		for _, existing := range metadata.Maintainers {
+			if _, ok := teams[existing.Name]; !ok {
+				updated = append(updated, existing)
+			}
+		}
+		var changes []string
+		if !equalChartMaintainers(metadata.Maintainers, updated) {
+			var value yaml.Node
+			if err := value.Encode(updated); err != nil {
+				return err
+			}
+			setMappingValue(root, "maintainers", &value)
+			changes = append(changes, fmt.Sprintf("maintainer [%s]", m.Name))
+		}
+		if stampAnnotation && metadata.Annotations[validate.MaintainerTeamAnnotation] != m.Name {
+			annotations := mappingValue(root, "annotations")
+			if annotations == nil || annotations.Kind != yaml.MappingNode {
+				annotations = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				setMappingValue(root, "annotations", annotations)
+			}
+			setMappingValue(annotations, validate.MaintainerTeamAnnotation, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.Name})
+			changes = append(changes, fmt.Sprintf("annotation [%s]", validate.MaintainerTeamAnnotation))
+		}
+		if len(changes) == 0 {
+			p.add(planSkip, "chart", fmt.Sprintf("[%s]", path), nil)
+			return nil
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return err
+		}
+		out := buf.Bytes()
+		p.add(planUpdate, "chart", fmt.Sprintf("[%s] %s", path, strings.Join(changes, " and ")), func() error {
+			return os.WriteFile(path, out, 0o644)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func equalChartMaintainers(a, b []chartMaintainer) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// setMappingValue replaces the value of key in a mapping node, appending the key if it is missing
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
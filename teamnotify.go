@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// slackPostMessageURL is the Web API method the bot token posts with
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// TeamSlack routes the messages meant for a team to its Contact.SlackChannel, through the incoming webhook of the
+// channel if there is one, or else with the bot token. Like the notification URLs, the token and webhooks can name
+// environment variables
+type TeamSlack struct {
+	Token string `yaml:"token"`
+	// Webhooks maps channels, e.g. #team-area1, to their incoming webhook
+	Webhooks map[string]string `yaml:"webhooks"`
+}
+
+// teamNotifyFlags tell validate where its findings are sent: the notifications or Slack channels of the teams, their
+// emails, and PagerDuty for the critical charts
+type teamNotifyFlags struct {
+	notify bool
+	email  bool
+	page   bool
+}
+
+// teamMessage is what is sent to a team, a title followed by a list of items, e.g. findings or issues, along with
+// what the templates get to render it differently
+type teamMessage struct {
+	team        *Maintainer
+	title       string
+	items       []teamMessageItem
+	source      string
+	findings    validate.Results
+	issues      []*remoteIssue
+	maintainers Maintainers
+}
+
+// teamMessageItem is an item of a teamMessage, linking to url if it is set
+type teamMessageItem struct {
+	text string
+	url  string
+}
+
+// teamFindings returns a message per team listing the findings about its charts, teams without findings get none
+// and findings about charts no team maintains, e.g. unowned charts, are not routed
+func teamFindings(config *Config, maintainers Maintainers, results validate.Results, source string) []teamMessage {
+	byTeam := make(map[*Maintainer]validate.Results)
+	for _, r := range results {
+		if r.Chart == "" {
+			continue
+		}
+		m, _ := maintainers.FindChart(validate.CanonicalChartName(config.Aliases, r.Chart))
+		if m == nil {
+			continue
+		}
+		byTeam[m] = append(byTeam[m], r)
+	}
+	var messages []teamMessage
+	for _, m := range maintainers {
+		findings := byTeam[m]
+		if len(findings) == 0 {
+			continue
+		}
+		message := teamMessage{team: m, source: source, findings: findings, maintainers: maintainers}
+		message.title = fmt.Sprintf("%s about the charts of %s in [%s]", pluralize(len(findings), "finding"), m.Name, source)
+		for _, r := range findings {
+			message.items = append(message.items, teamMessageItem{text: r.String()})
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// teamIssues returns a message per team listing the tracking issues created for its charts
+func teamIssues(maintainers Maintainers, created map[*Maintainer][]*remoteIssue, repo, release string) []teamMessage {
+	var messages []teamMessage
+	for _, m := range maintainers {
+		issues := created[m]
+		if len(issues) == 0 {
+			continue
+		}
+		message := teamMessage{team: m, source: repo, issues: issues, maintainers: maintainers}
+		message.title = fmt.Sprintf("%s created for the charts of %s in [%s]", pluralize(len(issues), release+" tracking issue"), m.Name, repo)
+		for _, issue := range issues {
+			message.items = append(message.items, teamMessageItem{text: fmt.Sprintf("#%d %s", issue.Number, issue.Title), url: issue.URL})
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// section is the built-in layout of the message
+func (m teamMessage) section() cardSection {
+	return cardSection{title: "cowhand: " + m.title, items: m.items}
+}
+
+func (m teamMessage) templateData() notificationData {
+	return notificationData{Title: m.title, Source: m.source, Team: m.team, Findings: m.findings, Issues: m.issues, Maintainers: m.maintainers}
+}
+
+// notifyTeams sends every message to the notifications of its team in the config, or else to its Slack channel. A
+// team that cannot be reached is logged and does not stop the others
+func notifyTeams(ctx context.Context, config *Config, messages []teamMessage) error {
+	var failed int
+	for _, message := range messages {
+		name := message.team.Name
+		if notifications := config.TeamNotifications[name]; len(notifications) > 0 {
+			for _, n := range notifications {
+				if err := n.sendMessage(ctx, &config.Templates, message); err != nil {
+					slog.Error("failed to notify team", "team", name, "type", n.Type, "error", err)
+					failed++
+					continue
+				}
+				slog.Info("notified team", "team", name, "type", n.Type)
+			}
+			continue
+		}
+		channel := message.team.Contact.SlackChannel
+		if channel == "" {
+			slog.Warn("team has no slack channel nor notifications, not notified", "team", name)
+			continue
+		}
+		text, templated, err := config.Templates.execute(notificationSlack, message.templateData())
+		if !templated {
+			text = slackSectionsText([]cardSection{message.section()})
+		}
+		if err == nil {
+			err = config.TeamSlack.post(ctx, channel, text)
+		}
+		if err != nil {
+			slog.Error("failed to notify team", "team", name, "channel", channel, "error", err)
+			failed++
+			continue
+		}
+		slog.Info("notified team", "team", name, "channel", channel)
+	}
+	if failed > 0 {
+		return fmt.Errorf("error: failed to send %s", pluralize(failed, "team notification"))
+	}
+	return nil
+}
+
+// post sends the text to the channel, an incoming webhook answers ok while the Web API answers a JSON body that
+// tells whether the message was posted
+func (s TeamSlack) post(ctx context.Context, channel, text string) error {
+	webhook := s.Webhooks[channel]
+	if webhook == "" {
+		webhook = s.Webhooks[strings.TrimPrefix(channel, "#")]
+	}
+	if webhook != "" {
+		return Notification{Type: notificationSlack, URL: webhook}.post(ctx, slackText{text})
+	}
+	token, err := resolveSecret(ctx, s.Token)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return errors.New("error: no webhook for the channel and no slack token in the config file")
+	}
+	data, err := json.Marshal(struct {
+		Channel string `json:"channel"`
+		Text    string `json:"text"`
+	}{strings.TrimPrefix(channel, "#"), text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slackPostMessageURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := notifyClient.Do(req)
+	if urlErr := (*neturl.Error)(nil); errors.As(err, &urlErr) {
+		return fmt.Errorf("error: slack notification failed: %w", urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("error: slack notification failed: %s", resp.Status)
+	}
+	if !result.OK {
+		return fmt.Errorf("error: slack notification failed: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// teamSummary is a team of teams --output json
+type teamSummary struct {
+	Name         string `json:"name"`
+	Email        string `json:"email,omitempty"`
+	SlackChannel string `json:"slackChannel,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Charts       int    `json:"charts"`
+}
+
+func runTeams(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		output              string
+		tmpl                queryTemplateFlag
+	)
+	fs := flag.NewFlagSet("teams", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&output, "output", "text", "output format, text or json")
+	tmpl.register(fs)
+	fs.Parse(args)
+	if output != "text" && output != "json" {
+		return fmt.Errorf("error: unknown output [%s], use text or json", output)
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	if tmpl.set() {
+		var items []queryItem
+		for _, m := range maintainers {
+			items = append(items, queryItem{Team: m})
+		}
+		return tmpl.write(os.Stdout, items)
+	}
+	if output == "json" {
+		teams := make([]teamSummary, 0, len(maintainers))
+		for _, m := range maintainers {
+			teams = append(teams, teamSummary{
+				Name:         m.Name,
+				Email:        m.Contact.Email,
+				SlackChannel: m.Contact.SlackChannel,
+				URL:          m.Contact.URL,
+				Charts:       len(m.Charts),
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(teams)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEAM\tCHARTS\tCONTACT")
+	for _, m := range maintainers {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", m.Name, len(m.Charts), m.Contact)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// NotificationTemplates override the built-in messages of the notifiers with Go templates executed with a
+// notificationData, e.g. {{ .Title }}{{ range .Findings }}\n- {{ .Message }}{{ end }}. An empty template keeps the
+// built-in message, the webhook notifications are JSON and are never templated
+type NotificationTemplates struct {
+	Slack   string `yaml:"slack"`
+	Teams   string `yaml:"teams"`
+	Discord string `yaml:"discord"`
+	// EmailSubject and EmailText are text templates, EmailHTML is an html/template escaping what it inserts
+	EmailSubject string `yaml:"emailSubject"`
+	EmailText    string `yaml:"emailText"`
+	EmailHTML    string `yaml:"emailHTML"`
+
+	text map[string]*template.Template
+	html *htmltemplate.Template
+}
+
+const (
+	templateEmailSubject = "emailSubject"
+	templateEmailText    = "emailText"
+)
+
+// notificationData is what the notification templates are executed with. Team and Maintainers are nil for the
+// notifications of daemon, which are not meant for a team, and Resolved is only set by them
+type notificationData struct {
+	// Title is the title of the built-in message, e.g. "2 findings about the charts of Team A in [maintainers.yaml]"
+	Title string
+	// Source is the maintainers file the findings are about, or the repository the issues were created in
+	Source      string
+	Team        *Maintainer
+	Findings    validate.Results
+	Resolved    validate.Results
+	Issues      []*remoteIssue
+	Maintainers Maintainers
+}
+
+// Chart returns the chart with the given name in the maintainers file, or nil, e.g. {{ ($.Chart .Chart).GithubLabels }}
+// in a range over the findings
+func (d notificationData) Chart(name string) *Chart {
+	_, chart := d.Maintainers.FindChart(name)
+	return chart
+}
+
+// Owner returns the team maintaining the chart with the given name, or nil
+func (d notificationData) Owner(name string) *Maintainer {
+	m, _ := d.Maintainers.FindChart(name)
+	return m
+}
+
+var templateFuncs = map[string]interface{}{
+	"join":      strings.Join,
+	"pluralize": pluralize,
+}
+
+// parse parses the templates of the config so that a broken one fails when the config is loaded
+func (t *NotificationTemplates) parse() error {
+	t.text = make(map[string]*template.Template)
+	for name, source := range map[string]string{
+		notificationSlack:    t.Slack,
+		notificationTeams:    t.Teams,
+		notificationDiscord:  t.Discord,
+		templateEmailSubject: t.EmailSubject,
+		templateEmailText:    t.EmailText,
+	} {
+		if source == "" {
+			continue
+		}
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return fmt.Errorf("invalid %s template: %w", name, err)
+		}
+		t.text[name] = tmpl
+	}
+	if t.EmailHTML != "" {
+		tmpl, err := htmltemplate.New("emailHTML").Funcs(templateFuncs).Parse(t.EmailHTML)
+		if err != nil {
+			return fmt.Errorf("invalid emailHTML template: %w", err)
+		}
+		t.html = tmpl
+	}
+	return nil
+}
+
+// execute executes the text template of the given name, ok is false if the config does not override it
+func (t *NotificationTemplates) execute(name string, data notificationData) (text string, ok bool, err error) {
+	tmpl := t.text[name]
+	if tmpl == nil {
+		return "", false, nil
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", true, fmt.Errorf("error: failed to execute the %s template: %w", name, err)
+	}
+	return b.String(), true, nil
+}
+
+// executeHTML is execute for the emailHTML template
+func (t *NotificationTemplates) executeHTML(data notificationData) (html string, ok bool, err error) {
+	if t.html == nil {
+		return "", false, nil
+	}
+	var b strings.Builder
+	if err := t.html.Execute(&b, data); err != nil {
+		return "", true, fmt.Errorf("error: failed to execute the emailHTML template: %w", err)
+	}
+	return b.String(), true, nil
+}
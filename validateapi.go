@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+// maxValidateRequest bounds the maintainers and index files of a POST /v1/validate
+const maxValidateRequest = 50 << 20
+
+// validateServer validates the maintainers files posted to POST /v1/validate, so that CI systems can use a central
+// cowhand instead of installing it. The request is a multipart form with the maintainers file in the maintainers
+// field and optionally the index file in index, or the URL of an index or chart repository in indexURL, e.g.
+//
+//	curl -H "Authorization: Bearer $TOKEN" -F maintainers=@maintainers.yaml -F indexURL=https://charts.rancher.io \
+//	  http://cowhand:8080/v1/validate
+//
+// The requests carry the token of the server as a bearer token, and indexURL must be one of the validateIndexURLs of
+// the config, so that the server never downloads what the requests choose
+type validateServer struct {
+	config *Config
+	token  []byte
+}
+
+// validateResponse is the response of POST /v1/validate, valid is false when there are errors
+type validateResponse struct {
+	Valid    bool             `json:"valid"`
+	Findings validate.Results `json:"findings"`
+}
+
+func (s *validateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r.Header.Get("Authorization")) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSONError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxValidateRequest)
+	if err := r.ParseMultipartForm(maxValidateRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse the form: %v", err))
+		return
+	}
+	maintainersData, err := formFile(r.MultipartForm, "maintainers")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var indexData []byte
+	if _, ok := r.MultipartForm.File["index"]; ok {
+		if indexData, err = formFile(r.MultipartForm, "index"); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	results, err := s.validate(r.Context(), maintainersData, indexData, r.FormValue("indexURL"))
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, time.Time{}, validateResponse{Valid: !results.HasErrors(), Findings: results})
+}
+
+// authorized reports whether the Authorization header carries the token of the server as a bearer token
+func (s *validateServer) authorized(authorization string) bool {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	return ok && len(s.token) > 0 && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), s.token) == 1
+}
+
+// validate validates the maintainers file against the index file, or the index downloaded from indexURL, or on its
+// own when there is neither. The error is set when a file cannot be loaded
+func (s *validateServer) validate(ctx context.Context, maintainersData, indexData []byte, indexURL string) (validate.Results, error) {
+	var maintainers Maintainers
+	if err := decodeYAMLFile(bytes.NewReader(maintainersData), &maintainers); err != nil {
+		return nil, fmt.Errorf("failed to decode the maintainers file: %w", err)
+	}
+	index, indexFilePath, err := s.index(ctx, indexData, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	results, err := validateMaintainersResults(ctx, s.config, maintainers, index, "maintainers.yaml", indexFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = validate.Results{}
+	}
+	return results, nil
+}
+
+// index returns the index and how to name it in the findings, nil when there is none. Only the remote indexes of
+// validateIndexURLs are downloaded, past the index cache, the server never reads its own files for a request
+func (s *validateServer) index(ctx context.Context, data []byte, url string) (*repo.IndexFile, string, error) {
+	if data != nil {
+		index, err := decodeIndex(data, "index.yaml")
+		return index, "index.yaml", err
+	}
+	if url == "" {
+		return nil, "", nil
+	}
+	if !isRemote(url) && !isOCI(url) {
+		return nil, "", fmt.Errorf("index URL [%s] is not an http(s) or oci:// URL", url)
+	}
+	url = repositoryIndexURL(url)
+	allowed := false
+	for _, u := range s.config.ValidateIndexURLs {
+		if repositoryIndexURL(u) == url {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, "", fmt.Errorf("index URL [%s] is not one of the validateIndexURLs of the config, upload the index instead", url)
+	}
+	var source IndexSource = ociIndexSource{ref: url}
+	if isRemote(url) {
+		source = httpIndexSource{url: url, uncached: true}
+	}
+	index, err := source.Load(ctx)
+	return index, url, err
+}
+
+// repositoryIndexURL returns the URL of the index of a chart repository URL, other URLs as they are
+func repositoryIndexURL(url string) string {
+	if isRemote(url) && !strings.HasSuffix(url, ".yaml") && !strings.HasSuffix(url, ".yml") {
+		return strings.TrimSuffix(url, "/") + "/index.yaml"
+	}
+	return url
+}
+
+// formFile reads the file uploaded in the field of the form
+func formFile(form *multipart.Form, field string) ([]byte, error) {
+	files := form.File[field]
+	if len(files) == 0 {
+		return nil, fmt.Errorf("the %s field is required", field)
+	}
+	if len(files) > 1 {
+		return nil, fmt.Errorf("the %s field holds %d files, expected one", field, len(files))
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidateServer(t *testing.T) {
+	const indexData = "apiVersion: v1\nentries:\n  fleet:\n    - name: fleet\n      version: 104.0.0\n  unowned:\n    - name: unowned\n      version: 1.0.0\n"
+	var downloads atomic.Int32
+	index := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		io.WriteString(w, indexData)
+	}))
+	defer index.Close()
+	defer func(dir string) { indexCache.dir = dir }(indexCache.dir)
+	indexCache.dir = t.TempDir()
+
+	s := &validateServer{config: &Config{ValidateIndexURLs: []string{index.URL + "/charts/"}}, token: []byte("token")}
+	maintainers := "- name: team-a\n  contact:\n    email: a@example.com\n  charts:\n    - name: fleet\n"
+	post := func(authorization string, fields map[string]string) (int, validateResponse) {
+		t.Helper()
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		for name, value := range fields {
+			if name == "indexURL" {
+				form.WriteField(name, value)
+				continue
+			}
+			file, _ := form.CreateFormFile(name, name+".yaml")
+			io.WriteString(file, value)
+		}
+		form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/v1/validate", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		var resp validateResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	for _, authorization := range []string{"", "Bearer other", "token", "Basic dG9rZW4="} {
+		if code, _ := post(authorization, map[string]string{"maintainers": maintainers}); code != http.StatusUnauthorized {
+			t.Errorf("authorization [%s]: got status %d, want %d", authorization, code, http.StatusUnauthorized)
+		}
+	}
+	if code, resp := post("Bearer token", map[string]string{"maintainers": maintainers}); code != http.StatusOK || !resp.Valid {
+		t.Errorf("maintainers file on its own: got status %d and %+v", code, resp)
+	}
+	// Only the URLs of the config are downloaded, whatever else a request names
+	for _, url := range []string{index.URL + "/other/index.yaml", "http://169.254.169.254/latest/meta-data/index.yaml", "file:///etc/passwd"} {
+		if code, _ := post("Bearer token", map[string]string{"maintainers": maintainers, "indexURL": url}); code != http.StatusUnprocessableEntity {
+			t.Errorf("index URL [%s]: got status %d, want %d", url, code, http.StatusUnprocessableEntity)
+		}
+	}
+	if downloads.Load() != 0 {
+		t.Fatalf("downloaded %d indexes that are not in the config", downloads.Load())
+	}
+	for i := 0; i < 2; i++ {
+		code, resp := post("Bearer token", map[string]string{"maintainers": maintainers, "indexURL": index.URL + "/charts"})
+		if code != http.StatusOK || len(resp.Findings) == 0 || !strings.Contains(resp.Findings[0].Chart, "unowned") {
+			t.Errorf("index URL of the config: got status %d and %+v", code, resp)
+		}
+	}
+	// The downloads of the requests are not cached, so requests cannot fill the disk of the server
+	if downloads.Load() != 2 {
+		t.Errorf("got %d downloads for two requests, want 2", downloads.Load())
+	}
+	if entries, _ := os.ReadDir(indexCache.dir); len(entries) != 0 {
+		t.Errorf("cached the downloads of requests: %v", entries)
+	}
+	if code, resp := post("Bearer token", map[string]string{"maintainers": maintainers, "index": indexData}); code != http.StatusOK || len(resp.Findings) == 0 {
+		t.Errorf("uploaded index: got status %d and %+v", code, resp)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pennyscissors/go-playground/pkg/validate"
+)
+
+func runWho(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		configFilePath      string
+		tmpl                queryTemplateFlag
+	)
+	var chartName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chartName, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("who", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&configFilePath, "config", defaultConfigFile, "path to the config file holding the chart aliases")
+	tmpl.register(fs)
+	fs.Parse(args)
+	if chartName == "" {
+		chartName = fs.Arg(0)
+	}
+	if chartName == "" {
+		return errors.New("error: usage: cowhand who <chart>")
+	}
+
+	config, err := loadConfig(configFilePath)
+	if err != nil {
+		return err
+	}
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	canonical := validate.CanonicalChartName(config.Aliases, chartName)
+	var items []queryItem
+	for _, m := range maintainers {
+		for i := range m.Charts {
+			if chart := &m.Charts[i]; chart.Name == chartName || validate.CanonicalChartName(config.Aliases, chart.Name) == canonical {
+				items = append(items, queryItem{Team: m, Chart: chart})
+			}
+		}
+	}
+	if len(items) > 0 && tmpl.set() {
+		return tmpl.write(os.Stdout, items)
+	}
+	for i, item := range items {
+		m, chart := item.Team, item.Chart
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("chart:    %s\n", chart.Name)
+		if chart.MaintainedVersions != "" {
+			fmt.Printf("versions: %s\n", chart.MaintainedVersions)
+		}
+		if len(chart.Repositories) > 0 {
+			fmt.Printf("repos:    %s\n", strings.Join(chart.Repositories, ", "))
+		}
+		fmt.Printf("team:     %s\n", m.Name)
+		for _, contact := range []struct{ name, value string }{
+			{"email", m.Contact.Email},
+			{"slack", m.Contact.SlackChannel},
+			{"url", m.Contact.URL},
+		} {
+			if contact.value != "" {
+				fmt.Printf("%-9s %s\n", contact.name+":", contact.value)
+			}
+		}
+		if len(chart.GithubLabels) > 0 {
+			fmt.Printf("labels:   %s\n", strings.Join(chart.GithubLabels, ", "))
+		}
+	}
+	if len(items) > 0 {
+		return nil
+	}
+	if suggestions := similarChartNames(maintainers.ChartNames(), chartName); len(suggestions) > 0 {
+		return fmt.Errorf("error: chart [%s] is not in maintainers file [%s], did you mean [%s]?", chartName, maintainersFilePath, strings.Join(suggestions, "], ["))
+	}
+	return fmt.Errorf("error: chart [%s] is not in maintainers file [%s]", chartName, maintainersFilePath)
+}
+
+// similarChartNames returns up to five of names that contain name or are a few edits away from it, closest first
+func similarChartNames(names []string, name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	maxDistance := max(2, len(name)/3)
+	for _, n := range names {
+		distance := editDistance(n, name)
+		if strings.Contains(n, name) || strings.Contains(name, n) {
+			distance = min(distance, 1)
+		}
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{n, distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	var similar []string
+	for i := 0; i < len(candidates) && i < 5; i++ {
+		similar = append(similar, candidates[i].name)
+	}
+	return similar
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
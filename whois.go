@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func runWhois(ctx context.Context, args []string) error {
+	var (
+		maintainersFilePath string
+		email               string
+		slackChannel        string
+		contactURL          string
+		tmpl                queryTemplateFlag
+	)
+	fs := flag.NewFlagSet("whois", flag.ExitOnError)
+	fs.StringVar(&maintainersFilePath, "maintainers-file", defaultMaintainersFile, "path to the maintainers file")
+	fs.StringVar(&email, "email", "", "list the teams with this email, ignoring case")
+	fs.StringVar(&slackChannel, "slack", "", "list the teams with this slack channel, with or without the leading #")
+	fs.StringVar(&contactURL, "url", "", "list the teams with this url")
+	tmpl.register(fs)
+	fs.Parse(args)
+	if email == "" && slackChannel == "" && contactURL == "" {
+		return errors.New("error: usage: cowhand whois --email <address> | --slack <channel> | --url <url>")
+	}
+
+	maintainers, err := decodeMaintainersFile(ctx, maintainersFilePath)
+	if err != nil {
+		return err
+	}
+	teams := maintainers.WithContact(Contact{Email: email, SlackChannel: slackChannel, URL: contactURL})
+	if len(teams) == 0 {
+		return fmt.Errorf("error: no team in maintainers file [%s] has contact [%s]", maintainersFilePath, Contact{Email: email, SlackChannel: slackChannel, URL: contactURL})
+	}
+	if tmpl.set() {
+		var items []queryItem
+		for _, m := range teams {
+			if len(m.Charts) == 0 {
+				items = append(items, queryItem{Team: m})
+			}
+			for i := range m.Charts {
+				items = append(items, queryItem{Team: m, Chart: &m.Charts[i]})
+			}
+		}
+		return tmpl.write(os.Stdout, items)
+	}
+	for i, m := range teams {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%s)\n", m.Name, m.Contact)
+		for _, chart := range m.Charts {
+			fmt.Printf("  %s\n", chart.Name)
+		}
+	}
+	return nil
+}